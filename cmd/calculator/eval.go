@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+// Exit codes of the eval subcommand.
+const (
+	exitOK    = 0
+	exitError = 1 // the expression could not be evaluated
+	exitUsage = 2 // bad flags or settings
+)
+
+// evalResult is the JSON output of the eval subcommand. Either Result and
+// Value or Error and Kind are set.
+type evalResult struct {
+	Expression string   `json:"expression"`
+	Result     string   `json:"result,omitempty"` // as the LCD shows it
+	Value      *float64 `json:"value,omitempty"`  // the result as a number
+	Error      string   `json:"error,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+}
+
+// errorKind names the kind of an evaluation error for scripts.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, expr.ErrSyntax):
+		return "syntax"
+	case errors.Is(err, expr.ErrDivisionByZero):
+		return "division-by-zero"
+	case errors.Is(err, expr.ErrOverflow):
+		return "overflow"
+	case errors.Is(err, expr.ErrDomain):
+		return "domain"
+	}
+	return "error"
+}
+
+// runEval runs `calculator eval [flags] expression`: it evaluates the
+// expression with the settings of the config file and prints the result
+// formatted as the calculator shows it. It returns the exit code.
+func runEval(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: calculator eval [flags] expression\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	cfg := config.Default()
+	configPath := fs.String("config", "", "read settings from `file` instead of the default config file")
+	output := fs.String("format", "plain", "output `format`: plain or json")
+	numberFlags(fs, &cfg)
+
+	_, cfgPath, err := parseSettings(fs, args, &cfg, configPath)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(stderr, "Invalid config:\n%v\n", err)
+		}
+		return exitUsage
+	}
+	if *output != "plain" && *output != "json" {
+		fmt.Fprintf(stderr, "unknown output format %q, use plain or json\n", *output)
+		return exitUsage
+	}
+	format, err := cfg.Format()
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid config:\n%v\n", err)
+		return exitUsage
+	}
+	angle, err := cfg.AngleUnit()
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid config:\n%v\n", err)
+		return exitUsage
+	}
+	env, err := loadDefinitions(cfgPath, expr.Env{Angle: angle})
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid definitions:\n%v\n", err)
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	text := strings.Join(fs.Args(), " ")
+	r := evaluate(text, env, format)
+	if *output == "json" {
+		enc := json.NewEncoder(stdout)
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitError
+		}
+	} else if r.Error == "" {
+		fmt.Fprintln(stdout, r.Result)
+	} else {
+		fmt.Fprintf(stderr, "Error: %s\n", r.Error)
+	}
+	if r.Error != "" {
+		return exitError
+	}
+	return exitOK
+}
+
+// evaluate evaluates text in env and formats the result with f.
+func evaluate(text string, env expr.Env, f numfmt.Format) evalResult {
+	r := evalResult{Expression: text}
+	v, err := env.Eval(text)
+	if err != nil {
+		r.Error, r.Kind = err.Error(), errorKind(err)
+		return r
+	}
+	// The value is the result as rounded for the display
+	result := f.Result(v)
+	v, _ = strconv.ParseFloat(result, 64)
+	r.Result = f.Display(result)
+	r.Value = &v
+	return r
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// eval runs the eval subcommand without a config file.
+func eval(t *testing.T, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var out, errOut strings.Builder
+	code = runEval(args, &out, &errOut)
+	return out.String(), errOut.String(), code
+}
+
+func TestEvalPlain(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"(12.5 * 4) - 3"}, "47\n"},
+		{[]string{"2", "+", "3", "*", "4"}, "14\n"},
+		{[]string{"1/3"}, "0.3333333333333333\n"},
+		{[]string{"--precision", "2", "1/3"}, "0.33\n"},
+		{[]string{"--number-format", "grouped", "1000 * 1000"}, "1,000,000\n"},
+		{[]string{"sin(30)"}, "0.5\n"},
+		{[]string{"--angle", "radians", "cos(0)"}, "1\n"},
+	}
+	for _, tt := range tests {
+		stdout, stderr, code := eval(t, tt.args...)
+		if stdout != tt.expected || stderr != "" || code != exitOK {
+			t.Errorf("eval %q: expected %q, got %q, %q, exit %d", tt.args, tt.expected, stdout, stderr, code)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		code     int
+	}{
+		{[]string{"1/0"}, "Error: division by zero\n", exitError},
+		{[]string{"(1 + 2"}, "Error: missing ')' at column 7\n", exitError},
+		{[]string{"asin(2)"}, "Error: domain error\n", exitError},
+		{[]string{"--rounding", "nearest", "1"}, "unknown rounding", exitUsage},
+		{[]string{"--format", "xml", "1"}, "unknown output format", exitUsage},
+		{nil, "Usage: calculator eval", exitUsage},
+	}
+	for _, tt := range tests {
+		stdout, stderr, code := eval(t, tt.args...)
+		if stdout != "" || !strings.Contains(stderr, tt.expected) || code != tt.code {
+			t.Errorf("eval %q: expected %q and exit %d, got %q, %q, exit %d", tt.args, tt.expected, tt.code, stdout, stderr, code)
+		}
+	}
+}
+
+func TestEvalJSON(t *testing.T) {
+	stdout, _, code := eval(t, "--format", "json", "--precision", "2", "2/3")
+	var r evalResult
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", stdout, err)
+	}
+	if code != exitOK || r.Expression != "2/3" || r.Result != "0.67" || r.Value == nil || *r.Value != 0.67 || r.Error != "" {
+		t.Errorf("Unexpected result %+v, exit %d", r, code)
+	}
+
+	stdout, _, code = eval(t, "--format", "json", "2 +")
+	r = evalResult{}
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", stdout, err)
+	}
+	if code != exitError || r.Kind != "syntax" || r.Error != "no number at column 4" || r.Value != nil {
+		t.Errorf("Unexpected error result %+v, exit %d", r, code)
+	}
+}
+
+func TestEvalUsesConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("precision = 1\nrounding = \"down\"\n"), 0o644)
+
+	if stdout, _, _ := eval(t, "--config", path, "2/3"); stdout != "0.6\n" {
+		t.Errorf("Expected the config file settings, got %q", stdout)
+	}
+	if stdout, _, _ := eval(t, "--config", path, "--rounding", "up", "2/3"); stdout != "0.7\n" {
+		t.Errorf("Expected flags to override the config file, got %q", stdout)
+	}
+}
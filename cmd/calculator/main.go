@@ -1,28 +1,196 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dmisiuk/goose-tui-calculator/internal/calculator"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
+	"github.com/dmisiuk/goose-tui-calculator/internal/theme"
 	"github.com/muesli/termenv"
 )
 
 func main() {
-	// Force TrueColor output when COLORTERM is set to truecolor
-	// This ensures colors work in VHS recordings and CI environments
-	// where auto-detection may fail
-	if os.Getenv("COLORTERM") == "truecolor" {
-		lipgloss.SetColorProfile(termenv.TrueColor)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "eval":
+			os.Exit(runEval(os.Args[2:], os.Stdout, os.Stderr))
+		case "repl":
+			os.Exit(runRepl(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		}
 	}
 
-	m := calculator.New()
-	p := tea.NewProgram(m)
+	cfg := config.Default()
+	configPath := flag.String("config", "", "read settings from `file` instead of the default config file")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration as TOML and exit")
+	tapePath := flag.String("tape", "", "turn print mode on and append the paper roll to `file`")
+	tapePane := flag.Bool("tape-pane", false, "show the printed tape next to the calculator")
+	flag.StringVar(&cfg.Layout, "layout", cfg.Layout, "load the keypad layout from a TOML `file`")
+	flag.StringVar(&cfg.Theme, "theme", cfg.Theme, "draw the calculator with a built-in theme `name` or a TOML/JSON theme file")
+	flag.StringVar(&cfg.Color, "color", cfg.Color, "color `mode`: auto, truecolor, 256, 16 or none")
+	flag.BoolVar(&cfg.Sound, "sound", cfg.Sound, "play key clicks and ring the terminal bell")
+	numberFlags(flag.CommandLine, &cfg)
+	flag.BoolVar(&cfg.Segments, "segments", cfg.Segments, "draw the display value with seven-segment digits")
+	flag.BoolVar(&cfg.Keypad, "keypad", cfg.Keypad, "start in keypad mode: enter is =, delete is CE")
+	flag.BoolVar(&cfg.Wrap, "wrap", cfg.Wrap, "wrap the keypad cursor around at the edges")
+	keys := flag.String("keys", "", "press the `keys` without a terminal, such as \"1+2=\", and print the display")
+	keysFile := flag.String("keys-file", "", "press the keys listed in `file` without a terminal and print the display")
+	trace := flag.Bool("trace", false, "with -keys or -keys-file, print the display after every key")
+
+	// The settings screen saves on top of the file, without the flags
+	fileCfg, cfgPath, err := parseSettings(flag.CommandLine, os.Args[1:], &cfg, configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	format, err := cfg.Format()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config:\n%v\n", err)
+		os.Exit(1)
+	}
+	angle, err := cfg.AngleUnit()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config:\n%v\n", err)
+		os.Exit(1)
+	}
+	profile, err := colorProfile(cfg.Color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	if err := calculator.CheckKeyBindings(cfg.Keys, cfg.Keypad); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid key bindings in %s:\n%v\n", cfgPath, err)
+		os.Exit(1)
+	}
+	if *printConfig {
+		if err := cfg.Write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error printing config: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	replaying := *keys != "" || *keysFile != ""
+	// Piped input is evaluated a line at a time instead of starting the UI
+	if !replaying && !isTerminal(os.Stdin) {
+		s, err := newSession(cfg, cfgPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid config:\n%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(runPipe(s, os.Stdin, os.Stdout, os.Stderr))
+	}
+	lipgloss.SetColorProfile(profile)
+
+	opts := []calculator.Option{
+		calculator.WithColorProfile(profile),
+		calculator.WithKeyBindings(cfg.Keys),
+		calculator.WithSound(cfg.Sound),
+		calculator.WithNumberFormat(format),
+		calculator.WithAngle(angle),
+		calculator.WithConfigFile(cfgPath, fileCfg),
+	}
+	if cfgPath != "" {
+		// Recorded macros are kept next to the config file
+		macrosPath := config.MacrosPath(cfgPath)
+		macros, err := config.LoadMacros(macrosPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid macros:\n%v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, calculator.WithMacros(macrosPath, macros))
+
+		env, err := loadDefinitions(cfgPath, expr.Env{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid definitions:\n%v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, calculator.WithDefinitions(config.DefinitionsPath(cfgPath), env))
+	}
+	if cfg.Keypad {
+		opts = append(opts, calculator.WithKeypadMode())
+	}
+	if cfg.Layout != "" {
+		layout, err := keypad.Load(cfg.Layout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid keypad layout:\n%v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, calculator.WithLayout(layout))
+	}
+	t, err := theme.Lookup(cfg.Theme)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid theme:\n%v\n", err)
+		os.Exit(1)
+	}
+	opts = append(opts, calculator.WithTheme(t))
+	if *tapePath != "" {
+		f, err := os.OpenFile(*tapePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening tape file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		opts = append(opts, calculator.WithTape(f))
+	}
+	if cfg.Segments {
+		opts = append(opts, calculator.WithSegments())
+	}
+	if cfg.Wrap {
+		opts = append(opts, calculator.WithWrap())
+	}
+	if *tapePane {
+		opts = append(opts, calculator.WithTapePane())
+	}
+
+	if replaying {
+		// Replays are silent: there is nobody to hear the clicks
+		opts = append(opts, calculator.WithSound(false))
+		m := calculator.New(opts...)
+		os.Exit(runReplay(m, *keys, *keysFile, *trace, os.Stdout, os.Stderr))
+	}
+
+	m := calculator.New(opts...)
+	// The alternate screen puts the calculator at the top-left corner so
+	// mouse coordinates line up with the rendered buttons. All motion is
+	// reported, not only while a button is held, so hovering moves the
+	// highlight.
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseAllMotion())
 
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// colorProfile returns the colors to draw with. In auto mode the terminal
+// is detected, and NO_COLOR turns color off.
+func colorProfile(mode string) (termenv.Profile, error) {
+	switch mode {
+	case "auto":
+		if termenv.EnvNoColor() {
+			return termenv.Ascii, nil
+		}
+		// Force TrueColor output when COLORTERM is set to truecolor
+		// This ensures colors work in VHS recordings and CI environments
+		// where auto-detection may fail
+		if os.Getenv("COLORTERM") == "truecolor" {
+			return termenv.TrueColor, nil
+		}
+		return lipgloss.ColorProfile(), nil
+	case "truecolor":
+		return termenv.TrueColor, nil
+	case "256":
+		return termenv.ANSI256, nil
+	case "16":
+		return termenv.ANSI, nil
+	case "none":
+		return termenv.Ascii, nil
+	}
+	return termenv.Ascii, fmt.Errorf("unknown color mode %q, use auto, truecolor, 256, 16 or none", mode)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/repl"
+)
+
+// replGreeting is printed when an interactive session starts.
+const replGreeting = "Goose Calculator - type help for commands, quit to leave."
+
+// runRepl runs `calculator repl [flags]`: an interactive session with
+// line editing and history on a terminal, a plain prompt on a dumb
+// terminal, and the piped mode when standard input is not a terminal. It
+// returns the exit code.
+func runRepl(args []string, stdin *os.File, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: calculator repl [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	cfg := config.Default()
+	configPath := fs.String("config", "", "read settings from `file` instead of the default config file")
+	numberFlags(fs, &cfg)
+
+	_, cfgPath, err := parseSettings(fs, args, &cfg, configPath)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(stderr, "Invalid config:\n%v\n", err)
+		}
+		return exitUsage
+	}
+	s, err := newSession(cfg, cfgPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid config:\n%v\n", err)
+		return exitUsage
+	}
+
+	switch {
+	case !isTerminal(stdin):
+		return runPipe(s, stdin, stdout, stderr)
+	case os.Getenv("TERM") == "dumb":
+		fmt.Fprintln(stdout, replGreeting)
+		err = repl.RunPrompt(s, stdin, stdout)
+	default:
+		fmt.Fprintln(stdout, replGreeting)
+		_, err = tea.NewProgram(repl.NewModel(s), tea.WithInput(stdin), tea.WithOutput(stdout)).Run()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// runPipe evaluates each line of r, as when input is piped into the
+// calculator. It returns the exit code, which is exitError when any line
+// failed.
+func runPipe(s *repl.Session, r io.Reader, stdout, stderr io.Writer) int {
+	ok, err := repl.RunLines(s, r, stdout, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading input: %v\n", err)
+		return exitError
+	}
+	if !ok {
+		return exitError
+	}
+	return exitOK
+}
+
+// newSession returns a line-at-a-time session with the number settings of
+// cfg and the user's variables and functions, which are kept next to the
+// config file at cfgPath and saved there when the session changes them.
+func newSession(cfg config.Config, cfgPath string) (*repl.Session, error) {
+	format, err := cfg.Format()
+	if err != nil {
+		return nil, err
+	}
+	angle, err := cfg.AngleUnit()
+	if err != nil {
+		return nil, err
+	}
+	env, err := loadDefinitions(cfgPath, expr.Env{Angle: angle})
+	if err != nil {
+		return nil, err
+	}
+	s := repl.NewSession(env, format)
+	if cfgPath != "" {
+		path := config.DefinitionsPath(cfgPath)
+		s.SaveWith(func(env expr.Env) error { return config.SaveDefinitions(path, env) })
+	}
+	return s, nil
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/calculator"
+)
+
+// replayer is a calculator that can be driven without a terminal.
+type replayer interface {
+	Replay(keys []tea.KeyMsg, step func(calculator.State)) calculator.State
+}
+
+// runReplay presses the keys of script, or of the file at path when
+// script is empty, and prints the final display and previous operation.
+// With trace, the state after every key is printed first. It returns the
+// exit code.
+func runReplay(m replayer, script, path string, trace bool, stdout, stderr io.Writer) int {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error reading keys: %v\n", err)
+			return exitError
+		}
+		script = string(data)
+	}
+	keys, err := calculator.ParseKeys(script)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid keys:\n%v\n", err)
+		return exitUsage
+	}
+
+	var step func(calculator.State)
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	if trace {
+		fmt.Fprintln(tw, "KEY\tDISPLAY\tPREVIOUS")
+		step = func(s calculator.State) {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Key, s.Display, s.PreviousDisplay)
+		}
+	}
+	final := m.Replay(keys, step)
+	if trace {
+		tw.Flush()
+		fmt.Fprintln(stdout)
+	}
+	fmt.Fprintf(stdout, "display: %s\nprevious: %s\n", final.Display, final.PreviousDisplay)
+	return exitOK
+}
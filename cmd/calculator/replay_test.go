@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/calculator"
+)
+
+func TestReplayKeys(t *testing.T) {
+	var out, errOut strings.Builder
+	code := runReplay(calculator.New(calculator.WithSound(false)), "2*4=", "", false, &out, &errOut)
+	expected := "display: 8\nprevious: 2 x 4 = 8\n"
+	if out.String() != expected || errOut.String() != "" || code != exitOK {
+		t.Errorf("expected %q, got %q, %q, exit %d", expected, out.String(), errOut.String(), code)
+	}
+}
+
+func TestReplayKeysFileWithTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.txt")
+	if err := os.WriteFile(path, []byte("# halve\n9 / 2\n=\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, errOut strings.Builder
+	runReplay(calculator.New(calculator.WithSound(false)), "", path, true, &out, &errOut)
+	if !strings.HasPrefix(out.String(), "KEY") || !strings.HasSuffix(out.String(), "display: 4.5\nprevious: 9 / 2 = 4.5\n") {
+		t.Errorf("unexpected trace:\n%s", out.String())
+	}
+}
+
+func TestReplayBadKeys(t *testing.T) {
+	var out, errOut strings.Builder
+	if code := runReplay(calculator.New(), "<nope>", "", false, &out, &errOut); code != exitUsage {
+		t.Errorf("expected exit %d for bad keys, got %d", exitUsage, code)
+	}
+	if code := runReplay(calculator.New(), "", "/nonexistent/keys.txt", false, &out, &errOut); code != exitError {
+		t.Errorf("expected exit %d for a missing file, got %d", exitError, code)
+	}
+}
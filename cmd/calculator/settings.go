@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+)
+
+// numberFlags adds the flags of the settings that change how results are
+// calculated and shown, bound to cfg.
+func numberFlags(fs *flag.FlagSet, cfg *config.Config) {
+	fs.IntVar(&cfg.Precision, "precision", cfg.Precision, "round results to `n` decimal places, -1 for as many as needed")
+	fs.StringVar(&cfg.Rounding, "rounding", cfg.Rounding, "rounding `mode`: half-up, half-even, down or up")
+	fs.StringVar(&cfg.NumberFormat, "number-format", cfg.NumberFormat, "number `format`: plain, grouped or scientific")
+	fs.StringVar(&cfg.Angle, "angle", cfg.Angle, "angle `unit` of trigonometric functions: degrees, radians or gradians")
+}
+
+// parseSettings parses args into fs, whose settings flags are bound to
+// cfg, then replaces cfg with the config file and sets again the flags
+// that were given, so they override it. configPath points at the value
+// of the --config flag. It returns the settings of the file alone and the
+// file's path.
+func parseSettings(fs *flag.FlagSet, args []string, cfg *config.Config, configPath *string) (config.Config, string, error) {
+	if err := fs.Parse(args); err != nil {
+		return config.Config{}, "", err
+	}
+	fileCfg, path, err := loadConfig(*configPath)
+	if err != nil {
+		return config.Config{}, "", err
+	}
+	given := map[string]string{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = f.Value.String() })
+	*cfg = fileCfg
+	for name, value := range given {
+		if err := fs.Set(name, value); err != nil {
+			return config.Config{}, "", err
+		}
+	}
+	return fileCfg, path, nil
+}
+
+// loadConfig reads the config file at path, or the default config file
+// when path is empty. Only the default file may be missing.
+func loadConfig(path string) (config.Config, string, error) {
+	if path == "" {
+		return config.LoadDefault()
+	}
+	cfg, err := config.Load(path)
+	return cfg, path, err
+}
+
+// loadDefinitions adds the user's variables and functions, kept next to
+// the config file at cfgPath, to env.
+func loadDefinitions(cfgPath string, env expr.Env) (expr.Env, error) {
+	if cfgPath == "" {
+		return env, nil
+	}
+	return config.LoadDefinitions(config.DefinitionsPath(cfgPath), env)
+}
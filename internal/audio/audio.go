@@ -101,7 +101,7 @@ func PlaySpecialActionSound() {
 // GetButtonType determines the button type based on the button label
 func GetButtonType(button string) ButtonType {
 	switch button {
-	case "AC", "=":
+	case "AC", "CE", "=":
 		return ButtonTypeSpecialAction
 	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
 		return ButtonTypeNumber
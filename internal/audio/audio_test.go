@@ -21,6 +21,7 @@ func TestGetButtonType(t *testing.T) {
 
 		// Special action buttons
 		{"AC", ButtonTypeSpecialAction},
+		{"CE", ButtonTypeSpecialAction},
 		{"=", ButtonTypeSpecialAction},
 
 		// Functional buttons
@@ -0,0 +1,89 @@
+package calculator
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+)
+
+// annunciator is a small indicator on the LCD, lit while its mode or
+// register is active.
+type annunciator struct {
+	label string
+	lit   func(m model) bool
+	// text, when set, is what the indicator shows while lit, at most as
+	// wide as label
+	text func(m model) string
+}
+
+// shown returns what a lit indicator shows.
+func (a annunciator) shown(m model) string {
+	if a.text == nil {
+		return a.label
+	}
+	return a.text(m)
+}
+
+// annunciators are drawn in this order, each in a fixed slot so an
+// indicator does not move when others light up. Only a departure from the
+// default state lights one, so there is no DEG for degrees, the default
+// angle unit: the row stays blank until something is switched on, and the
+// compact layout, which shares the row with the previous operation, keeps
+// all of its width for it.
+var annunciators = []annunciator{
+	{"−", func(m model) bool { return strings.HasPrefix(m.display, "-") }, nil},
+	{"E", func(m model) bool { return m.isError }, nil},
+	{"PRT", func(m model) bool { return m.printing }, nil},
+	{"CHK", func(m model) bool { return m.reviewing }, nil},
+	{"KP", func(m model) bool { return m.keypadMode }, nil},
+	{"REC", func(m model) bool { return m.recording }, nil},
+	{"M", func(m model) bool { return len(m.definitions.Vars) > 0 }, nil},
+	{"GRAD", func(m model) bool { return m.angle != expr.Degrees }, angleLabel},
+}
+
+// angleLabel returns the indicator of the angle unit.
+func angleLabel(m model) string {
+	switch m.angle {
+	case expr.Radians:
+		return "RAD"
+	case expr.Gradians:
+		return "GRAD"
+	}
+	return ""
+}
+
+// annunciatorLine returns the indicators for the current state, at most
+// width columns wide. Unlit slots are blank; when all slots do not fit,
+// only the lit indicators are shown.
+func (m model) annunciatorLine(width int) string {
+	var slots, lit []string
+	for _, a := range annunciators {
+		if a.lit(m) {
+			text := a.shown(m)
+			slots = append(slots, text+strings.Repeat(" ", lipgloss.Width(a.label)-lipgloss.Width(text)))
+			lit = append(lit, text)
+		} else {
+			slots = append(slots, strings.Repeat(" ", lipgloss.Width(a.label)))
+		}
+	}
+	line := strings.Join(slots, " ")
+	if lipgloss.Width(line) > width {
+		line = strings.Join(lit, " ")
+	}
+	return strings.TrimRight(line, " ")
+}
+
+// renderAnnunciatorsBeside puts the indicators and the previous operation
+// on one line, as the compact layout has no room for a separate row. The
+// previous operation gives way when both do not fit.
+func (m model) renderAnnunciatorsBeside(previous string, width int) string {
+	line := m.annunciatorLine(width)
+	room := width - lipgloss.Width(line)
+	if line != "" {
+		room-- // space between the two
+	}
+	left := m.styles.annunciators.Render(line)
+	right := m.styles.previousDisplay.Width(width - lipgloss.Width(line)).Render(scrollWindow(previous, room, m.scroll))
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+}
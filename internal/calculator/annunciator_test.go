@@ -0,0 +1,83 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+)
+
+func TestAnnunciatorsFollowState(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     string
+		expected string
+	}{
+		{"nothing active", "12", ""},
+		{"negative", "12~", "−"},
+		{"error", "5/0=", "  E"},
+		{"print mode", "p", "    PRT"},
+		{"review", "1+2=r", "        CHK"},
+		{"several", "p3~", "−   PRT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := typeKeys(New(), runes(tt.keys)...)
+			if got := m.annunciatorLine(40); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAngleAnnunciator(t *testing.T) {
+	tests := []struct {
+		angle    expr.Angle
+		expected string
+	}{
+		{expr.Degrees, ""},
+		{expr.Radians, "RAD"},
+		{expr.Gradians, "GRAD"},
+	}
+	for _, tt := range tests {
+		m := New(WithAngle(tt.angle))
+		if got := strings.TrimSpace(m.annunciatorLine(40)); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.angle, tt.expected, got)
+		}
+	}
+
+	// The unit keeps its slot when other indicators light up
+	m := typeKeys(New(WithAngle(expr.Radians)), runes("p")...)
+	if got := m.annunciatorLine(40); got != "    PRT              RAD" {
+		t.Errorf("Expected PRT and RAD in their slots, got %q", got)
+	}
+}
+
+func TestAnnunciatorsCompressWhenNarrow(t *testing.T) {
+	m := typeKeys(New(), runes("1+2=r")...)
+	if got := m.annunciatorLine(8); got != "CHK" {
+		t.Errorf("Expected only lit indicators, got %q", got)
+	}
+}
+
+func TestAnnunciatorsInView(t *testing.T) {
+	m := typeKeys(New(), runes("p")...)
+	if !strings.Contains(m.View(), "PRT") {
+		t.Errorf("Expected PRT on the LCD:\n%s", m.View())
+	}
+
+	// The compact layout shares a line with the previous operation
+	m = resize(typeKeys(m, runes("12+3")...), 30, 14)
+	if !m.geometry().compact {
+		t.Fatalf("Expected compact layout")
+	}
+	found := false
+	for _, line := range strings.Split(m.View(), "\n") {
+		if strings.Contains(line, "PRT") && strings.Contains(line, "12 +") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected PRT beside 12 +:\n%s", m.View())
+	}
+}
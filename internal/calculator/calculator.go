@@ -2,81 +2,22 @@ package calculator
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dmisiuk/goose-tui-calculator/internal/audio"
-)
-
-var (
-	// Colors - using terminal defaults for backgrounds
-	displayBackground = lipgloss.Color("#1B5E4F") // Dark green LCD
-	displayText       = lipgloss.Color("#D5F5E3") // Light green LCD text
-	displayTextDim    = lipgloss.Color("#82C9B5") // Dim green
-
-	acButtonColor      = lipgloss.Color("#C0392B") // Red AC
-	numberButtonColor  = lipgloss.Color("#5D6D7E") // Gray numbers
-	operatorColor      = lipgloss.Color("#D68910") // Orange operators
-	functionalKeyColor = lipgloss.Color("#7F8C8D") // Light gray functional keys (+/-, %, .)
-	zeroButtonColor    = lipgloss.Color("#34495E") // Darker blue-gray for 0
-	equalsButtonColor  = lipgloss.Color("#E67E22") // Bright orange for equals
-
-	buttonTextColor = lipgloss.Color("#FFFFFF") // White text
-	logoTextColor   = lipgloss.Color("#FFFFFF") // White logo
-
-	// Logo
-	logoStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(logoTextColor).
-			Align(lipgloss.Center)
-
-	// LCD Display
-	displayContainerStyle = lipgloss.NewStyle().
-				Background(displayBackground).
-				Padding(1, 2)
-
-	displayStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(displayText).
-			Align(lipgloss.Right)
-
-	previousDisplayStyle = lipgloss.NewStyle().
-				Foreground(displayTextDim).
-				Align(lipgloss.Right)
-
-	// Buttons - wider for better look
-	baseButtonStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(buttonTextColor).
-			Align(lipgloss.Center).
-			Width(6).
-			Height(2)
-
-	numberButtonStyle     = baseButtonStyle.Copy().Background(numberButtonColor)
-	acButtonStyle         = baseButtonStyle.Copy().Background(acButtonColor)
-	operatorButtonStyle   = baseButtonStyle.Copy().Background(operatorColor)
-	functionalButtonStyle = baseButtonStyle.Copy().Background(functionalKeyColor)
-	zeroButtonStyle       = baseButtonStyle.Copy().Background(zeroButtonColor)
-	equalsButtonStyle     = baseButtonStyle.Copy().Background(equalsButtonColor)
-
-	// Visual feedback
-	highlightBackground      = lipgloss.Color("#FFD700")
-	pressedBackground        = lipgloss.Color("#FF4500")
-	directKeyboardBackground = lipgloss.Color("#6A5ACD")
-
-	highlightStyle      = baseButtonStyle.Copy().Background(highlightBackground).Foreground(lipgloss.Color("#000000"))
-	pressedStyle        = baseButtonStyle.Copy().Background(pressedBackground).Foreground(buttonTextColor)
-	directKeyboardStyle = baseButtonStyle.Copy().Background(directKeyboardBackground).Foreground(buttonTextColor)
-
-	// Calculator body - NO background, use terminal default
-	calculatorBodyStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#95A5A6")).
-				Padding(1, 2)
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+	"github.com/dmisiuk/goose-tui-calculator/internal/theme"
+	"github.com/muesli/termenv"
 )
 
 type tickMsg time.Time
@@ -98,9 +39,11 @@ const (
 type model struct {
 	display             string
 	previousDisplay     string
-	buttons             [][]string
+	layout              keypad.Layout
 	cursorX             int
 	cursorY             int
+	cursorCol           int
+	wrap                bool
 	operator            string
 	operand1            string
 	isOperand2          bool
@@ -114,133 +57,270 @@ type model struct {
 	pressedY            int
 	activationMethod    activationMethod
 	activationStartTime time.Time
+	printing            bool
+	showTape            bool
+	tape                *tape
+	undoStack           []calcState
+	redoStack           []calcState
+	entries             []entry
+	reviewing           bool
+	reviewIndex         int
+	reviewEdit          string
+	clipboard           io.Writer
+	notice              string
+	noticeTime          time.Time
+	width               int
+	height              int
+	mouseDown           bool
+	mouseDownX          int
+	mouseDownY          int
+	theme               theme.Theme
+	themes              []theme.Theme
+	styles              styles
+	colorProfile        termenv.Profile
+	segments            bool
+	scroll              int
+	showHelp            bool
+	keypadMode          bool
+	keyBindings         map[string][]string
+	sound               bool
+	numberFormat        numfmt.Format
+	angle               expr.Angle
+	showSettings        bool
+	settingsList        list.Model
+	configPath          string
+	config              config.Config
+	recording           bool
+	recorded            []string
+	macroPrompt         macroPrompt
+	macros              config.Macros
+	macrosPath          string
+	memoryPrompt        memoryPrompt
+	showMemory          bool
+	definitions         expr.Env
+	definitionsPath     string
+}
+
+// Option configures the model returned by New.
+type Option func(*model)
+
+// WithTape turns print mode on and appends every printed line to w.
+func WithTape(w io.Writer) Option {
+	return func(m *model) {
+		m.tape = newTape(w)
+		m.printing = true
+	}
 }
 
-type keyMap struct {
-	Up    key.Binding
-	Down  key.Binding
-	Left  key.Binding
-	Right key.Binding
-	Enter key.Binding
-	Quit  key.Binding
-	Esc   key.Binding
+// WithLayout replaces the classic keypad with l.
+func WithLayout(l keypad.Layout) Option {
+	return func(m *model) {
+		m.layout = l
+	}
 }
 
-var defaultKeyMap = keyMap{
-	Up:    key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move up")),
-	Down:  key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move down")),
-	Left:  key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "move left")),
-	Right: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "move right")),
-	Enter: key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "press button")),
-	Quit:  key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
-	Esc:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
+// WithSegments draws the display value with seven-segment digits.
+func WithSegments() Option {
+	return func(m *model) {
+		m.segments = true
+	}
 }
 
-func New() model {
-	return model{
+// WithSound turns the key click and terminal bell on or off. Sound is on
+// by default.
+func WithSound(on bool) Option {
+	return func(m *model) {
+		m.sound = on
+	}
+}
+
+// WithNumberFormat rounds and formats results with f.
+func WithNumberFormat(f numfmt.Format) Option {
+	return func(m *model) {
+		m.numberFormat = f
+	}
+}
+
+// WithAngle sets the angle unit of trigonometric functions in pasted
+// expressions.
+func WithAngle(a expr.Angle) Option {
+	return func(m *model) {
+		m.angle = a
+	}
+}
+
+// WithTapePane shows the printed tape in a pane next to the calculator.
+func WithTapePane() Option {
+	return func(m *model) {
+		m.showTape = true
+	}
+}
+
+func New(opts ...Option) model {
+	m := model{
 		display:         "0",
 		previousDisplay: "",
-		buttons: [][]string{
-			{"AC", "+/-", "%", "/"},
-			{"7", "8", "9", "x"},
-			{"4", "5", "6", "-"},
-			{"1", "2", "3", "+"},
-			{"0", ".", "="},
-		},
-		keys: defaultKeyMap,
+		layout:          keypad.Default(),
+		keys:            defaultKeyMap(),
+		tape:            newTape(nil),
+		theme:           theme.Classic(),
+		themes:          theme.Builtins(),
+		colorProfile:    termenv.TrueColor,
+		sound:           true,
+		numberFormat:    numfmt.Default(),
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	m.styles = newStyles(m.theme, m.colorProfile)
+	keys, err := keyMapFor(m.keypadMode, m.keyBindings)
+	if err != nil {
+		// Bindings that CheckKeyBindings rejects are dropped as a whole
+		// rather than half applied, and the LCD says so
+		m.keyBindings = nil
+		keys, _ = keyMapFor(m.keypadMode, nil)
+		m.notice, m.noticeTime = "KEYS IGNORED", time.Now()
 	}
+	m.keys = keys
+	return m
 }
 
-func (m model) Init() tea.Cmd { return nil }
+// Init starts the clock that clears a notice New put on the LCD.
+func (m model) Init() tea.Cmd {
+	if m.notice != "" {
+		return tick()
+	}
+	return nil
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tickMsg:
-		if m.activationMethod != activationNone && time.Since(m.activationStartTime) > time.Millisecond*300 {
+		if m.activationMethod != activationNone && !m.mouseDown && time.Since(m.activationStartTime) > time.Millisecond*300 {
 			m.activationMethod = activationNone
 			m.pressedX = -1
 			m.pressedY = -1
 		}
+		m = m.clearExpiredNotice()
 		return m, tick()
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case copiedMsg:
+		if msg.err != nil {
+			return m.showNotice("COPY FAILED")
+		}
+		return m.showNotice("COPIED")
+	case savedMsg:
+		if msg.err != nil {
+			return m.showNotice("SAVE FAILED")
+		}
+		return m.showNotice(msg.notice)
 	case tea.KeyMsg:
-		if btn, ok := mapKeyToButton(msg.String()); ok {
-			for y, row := range m.buttons {
-				for x, val := range row {
-					if val == btn {
-						m.pressedX = x
-						m.pressedY = y
-						m.activationMethod = activationDirectKeyboard
-						m.activationStartTime = time.Now()
-						break
-					}
-				}
-			}
-			updatedModel, cmd := m.handleButtonPress(btn)
-			if updated, ok := updatedModel.(model); ok {
-				return updated, tea.Batch(cmd, tick())
-			}
-			return updatedModel, tea.Batch(cmd, tick())
+		if m.showHelp {
+			return m.updateHelp(msg)
+		}
+		if m.showSettings {
+			return m.updateSettings(msg)
+		}
+		if m.showMemory {
+			return m.updateMemory(msg)
+		}
+		if m.macroPrompt != macroNone {
+			return m.updateMacroPrompt(msg)
+		}
+		if m.memoryPrompt != memoryNone {
+			return m.updateMemoryPrompt(msg)
+		}
+		if m.reviewing {
+			return m.updateReview(msg)
+		}
+		if isPaste(msg) {
+			return m.paste(string(msg.Runes))
+		}
+		if btn, ok := m.keys.button(msg); ok {
+			x, y := m.findButton(btn)
+			return m.press(btn, x, y, activationDirectKeyboard)
 		}
 		switch {
 		case key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Esc):
 			m.isQuitting = true
 			return m, tea.Quit
-		case key.Matches(msg, m.keys.Up):
-			if m.cursorY > 0 {
-				m.cursorY--
-				if m.cursorX >= len(m.buttons[m.cursorY]) {
-					m.cursorX = len(m.buttons[m.cursorY]) - 1
-				}
-			}
-		case key.Matches(msg, m.keys.Down):
-			if m.cursorY < len(m.buttons)-1 {
-				m.cursorY++
-				if m.cursorX >= len(m.buttons[m.cursorY]) {
-					m.cursorX = len(m.buttons[m.cursorY]) - 1
-				}
-			}
-		case key.Matches(msg, m.keys.Left):
-			if m.cursorX > 0 {
-				m.cursorX--
-			}
-		case key.Matches(msg, m.keys.Right):
-			if m.cursorX < len(m.buttons[m.cursorY])-1 {
-				m.cursorX++
-			}
+		case key.Matches(msg, m.keys.Print):
+			m.printing = !m.printing
+		case key.Matches(msg, m.keys.Tape):
+			m.showTape = !m.showTape
+		case key.Matches(msg, m.keys.Undo):
+			return m.undo(), nil
+		case key.Matches(msg, m.keys.Redo):
+			return m.redo(), nil
+		case key.Matches(msg, m.keys.Review):
+			return m.startReview(), nil
+		case key.Matches(msg, m.keys.Copy):
+			return m, m.copyCmd(m.display)
+		case key.Matches(msg, m.keys.CopyExpression):
+			return m, m.copyCmd(m.expression())
+		case key.Matches(msg, m.keys.Theme):
+			return m.nextTheme()
+		case key.Matches(msg, m.keys.Segments):
+			m.segments = !m.segments
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+		case key.Matches(msg, m.keys.Settings):
+			return m.openSettings(), nil
+		case key.Matches(msg, m.keys.KeypadMode):
+			return m.toggleKeypadMode()
+		case key.Matches(msg, m.keys.Record):
+			return m.toggleRecording()
+		case key.Matches(msg, m.keys.Play):
+			m.macroPrompt = macroPlay
+		case key.Matches(msg, m.keys.Store):
+			m.memoryPrompt = memoryStore
+		case key.Matches(msg, m.keys.Recall):
+			m.memoryPrompt = memoryRecall
+		case key.Matches(msg, m.keys.Variables):
+			m.showMemory = true
+		case key.Matches(msg, m.keys.ScrollLeft):
+			return m.scrollBy(1), nil
+		case key.Matches(msg, m.keys.ScrollRight):
+			return m.scrollBy(-1), nil
+		case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Down),
+			key.Matches(msg, m.keys.Left), key.Matches(msg, m.keys.Right),
+			key.Matches(msg, m.keys.RowStart), key.Matches(msg, m.keys.RowEnd),
+			key.Matches(msg, m.keys.Top), key.Matches(msg, m.keys.Bottom):
+			return m.navigate(msg), nil
 		case key.Matches(msg, m.keys.Enter):
-			button := m.buttons[m.cursorY][m.cursorX]
-			m.pressedX = m.cursorX
-			m.pressedY = m.cursorY
-			m.activationMethod = activationNavigation
-			m.activationStartTime = time.Now()
-			updatedModel, cmd := m.handleButtonPress(button)
-			if updated, ok := updatedModel.(model); ok {
-				return updated, tea.Batch(cmd, tick())
-			}
-			return updatedModel, tea.Batch(cmd, tick())
+			return m.press(m.layout.Key(m.cursorX, m.cursorY).Button(), m.cursorX, m.cursorY, activationNavigation)
 		}
 	case tea.MouseMsg:
-		if msg.Type == tea.MouseLeft {
-			for y, row := range m.buttons {
-				for x, val := range row {
-					if msg.Y == y+2 && msg.X >= x*6 && msg.X < x*6+5 {
-						m.pressedX = x
-						m.pressedY = y
-						m.activationMethod = activationNavigation
-						m.activationStartTime = time.Now()
-						updatedModel, cmd := m.handleButtonPress(val)
-						if updated, ok := updatedModel.(model); ok {
-							return updated, tea.Batch(cmd, tick())
-						}
-						return updatedModel, tea.Batch(cmd, tick())
-					}
-				}
-			}
+		if m.showHelp || m.showSettings || m.showMemory {
+			return m, nil
 		}
+		return m.updateMouse(msg)
 	}
 	return m, nil
 }
 
+// press activates button, showing feedback for the given method on the key
+// at (x, y).
+func (m model) press(button string, x, y int, method activationMethod) (tea.Model, tea.Cmd) {
+	m.pressedX = x
+	m.pressedY = y
+	m.activationMethod = method
+	m.activationStartTime = time.Now()
+	updatedModel, cmd := m.handleButtonPress(button)
+	return updatedModel, tea.Batch(cmd, tick())
+}
+
+// findButton returns the grid position of button, or (-1, -1) when the
+// keypad has no such key.
+func (m model) findButton(button string) (int, int) {
+	if x, y, ok := m.layout.Find(button); ok {
+		return x, y
+	}
+	return -1, -1
+}
+
 func (m model) HandleButtonPress(button string) (model, tea.Cmd) {
 	updatedModel, cmd := m.handleButtonPress(button)
 	if updated, ok := updatedModel.(model); ok {
@@ -254,17 +334,35 @@ func (m model) Display() string {
 }
 
 func (m model) handleButtonPress(button string) (tea.Model, tea.Cmd) {
-	m.lastButton = button
-	m.isError = false
+	m.undoStack = pushState(m.undoStack, m.state())
+	m.redoStack = nil
+	m.scroll = 0
+	if id, ok := actionFor(button); ok {
+		m = m.record(id)
+	}
+
+	if !m.sound {
+		return m.apply(button), nil
+	}
 
 	// Play audio feedback asynchronously
 	audio.PlayButtonSound(button)
 
+	return m.apply(button), func() tea.Msg { fmt.Print("\a"); return nil }
+}
+
+// apply runs a button press through the calculation engine.
+func (m model) apply(button string) model {
+	prevButton := m.lastButton
+	m.lastButton = button
+	m.isError = false
+
 	switch {
 	case isNumber(button):
 		if m.isOperand2 {
 			if m.operator == "" {
 				m.previousDisplay = ""
+				m.entries = nil
 			}
 			m.display = button
 			m.isOperand2 = false
@@ -278,16 +376,32 @@ func (m model) handleButtonPress(button string) (tea.Model, tea.Cmd) {
 			m.display += "."
 		}
 	case isOperator(button):
+		carried := prevButton == "=" && m.operator == ""
+		if carried {
+			m.print(tapeSubtotal, button)
+		} else {
+			m.print(m.display, button)
+		}
+		m.entries = appendEntry(m.entries, entry{value: m.display, op: button, carried: carried})
 		m.operand1 = m.display
 		m.operator = button
 		m.isOperand2 = true
 		m.previousDisplay = m.operand1 + " " + m.operator
 	case button == "AC":
+		m.print("", tapeClear)
+		m.feed()
+		m.entries = nil
 		m.display = "0"
 		m.previousDisplay = ""
 		m.operand1 = ""
 		m.operator = ""
 		m.isOperand2 = false
+	case button == "CE":
+		// Clear the value being entered, keeping the pending operation
+		m.display = "0"
+		if m.operator != "" {
+			m.isOperand2 = false
+		}
 	case button == "+/-":
 		if m.display != "0" {
 			if strings.HasPrefix(m.display, "-") {
@@ -297,16 +411,21 @@ func (m model) handleButtonPress(button string) (tea.Model, tea.Cmd) {
 			}
 		}
 	case button == "%":
+		m.print(m.display, "%")
 		val, _ := strconv.ParseFloat(m.display, 64)
-		m.display = fmt.Sprintf("%g", val/100)
+		m.display = m.numberFormat.Result(val / 100)
 	case button == "=":
 		if m.operand1 != "" && m.operator != "" {
 			operand2 := m.display
+			m.print(operand2, "=")
+			m.entries = appendEntry(m.entries, entry{value: operand2, op: "="})
 			val1, err1 := strconv.ParseFloat(m.operand1, 64)
 			val2, err2 := strconv.ParseFloat(operand2, 64)
 			if err1 != nil || err2 != nil {
 				m.display = "Error"
 				m.isError = true
+				m.print(m.display, tapeError)
+				m.feed()
 				break
 			}
 			var result float64
@@ -326,124 +445,190 @@ func (m model) handleButtonPress(button string) (tea.Model, tea.Cmd) {
 				result = val1 / val2
 			}
 			if !m.isError {
-				m.previousDisplay = fmt.Sprintf("%s %s %s = %g", m.operand1, m.operator, operand2, result)
-				m.display = fmt.Sprintf("%g", result)
+				m.display = m.numberFormat.Result(result)
+				m.previousDisplay = fmt.Sprintf("%s %s %s = %s", m.operand1, m.operator, operand2, m.display)
+				m.print(m.display, tapeTotal)
+			} else {
+				m.print(m.display, tapeError)
 			}
+			m.feed()
 			m.operand1 = ""
 			m.operator = ""
 			m.isOperand2 = true
 		}
 	}
 
-	return m, func() tea.Msg { fmt.Print("\a"); return nil }
+	return m
 }
 
 func isNumber(s string) bool { _, err := strconv.Atoi(s); return err == nil }
 
 func isOperator(s string) bool { return s == "+" || s == "-" || s == "x" || s == "/" }
 
-func mapKeyToButton(k string) (string, bool) {
-	if isNumber(k) {
-		return k, true
-	}
-	switch k {
-	case "+", "-", "/":
-		return k, true
-	case "*", "x":
-		return "x", true
-	case ".":
-		return ".", true
-	case "=":
-		return "=", true
-	case "c", "C":
-		return "AC", true
-	case "%":
-		return "%", true
-	case "~":
-		return "+/-", true
-	}
-	return "", false
-}
-
 func (m model) View() string {
 	if m.isQuitting {
 		return "Thanks for using the Goose Calculator!\n"
 	}
 
-	var b strings.Builder
+	if m.showHelp {
+		return m.renderHelpOverlay()
+	}
+	if m.showSettings {
+		return m.renderSettingsOverlay()
+	}
+	if m.showMemory {
+		return m.renderMemoryOverlay()
+	}
+
+	g := m.geometry()
+	if g.tooSmall {
+		return m.renderTooSmall()
+	}
+
+	body := m.renderBody(g)
+	if g.showTape {
+		return lipgloss.JoinHorizontal(lipgloss.Top, body, m.renderTapePane(lipgloss.Height(body)))
+	}
+	return body
+}
+
+func (m model) renderBody(g geometry) string {
+	return m.bodyStyle(g).Render(strings.Join(m.sections(g), m.sectionSeparator(g)))
+}
+
+// sections returns the parts of the calculator body from top to bottom.
+func (m model) sections(g geometry) []string {
+	sections := append(m.sectionsAboveKeypad(g), m.renderKeypad(g))
+	return append(sections, m.sectionsBelowKeypad(g)...)
+}
+
+// sectionsAboveKeypad returns the sections rendered above the keypad. The
+// compact layout keeps only the LCD.
+func (m model) sectionsAboveKeypad(g geometry) []string {
+	if g.compact {
+		return []string{m.renderLCD(g)}
+	}
+	return []string{m.renderLogo(g), m.renderLCD(g)}
+}
+
+// sectionsBelowKeypad returns the sections rendered below the keypad.
+func (m model) sectionsBelowKeypad(g geometry) []string {
+	if g.compact {
+		return nil
+	}
+	return []string{m.renderHelp(g)}
+}
+
+// sectionSeparator is placed between sections: a blank line, or just a
+// line break in the compact layout.
+func (m model) sectionSeparator(g geometry) string {
+	if g.compact {
+		return "\n"
+	}
+	return "\n\n"
+}
+
+func (m model) bodyStyle(g geometry) lipgloss.Style {
+	if g.compact {
+		return m.styles.body.Padding(0, 1)
+	}
+	return m.styles.body
+}
+
+func (m model) renderLogo(g geometry) string {
+	// Logo - match button grid width
+	return m.styles.logo.Width(g.gridWidth()).Render("🪿 GOOSE 🪿")
+}
+
+// lcdContainer returns the LCD style for geometry g.
+func (m model) lcdContainer(g geometry) lipgloss.Style {
+	container := m.styles.displayContainer
+	if g.compact {
+		container = container.Padding(0, 1)
+	}
+	return container
+}
+
+// lcdTextWidth returns the columns available for text on the LCD.
+func (m model) lcdTextWidth(g geometry) int {
+	return g.gridWidth() - m.lcdContainer(g).GetHorizontalPadding()
+}
+
+// lcdLines returns the previous operation and current value lines of the
+// LCD, or the review step and notice shown in their place.
+func (m model) lcdLines() (string, string) {
+	previous, current := m.previousDisplay, m.display
+	if m.reviewing {
+		previous, current = m.reviewLines()
+	}
+	previous, current = m.numberFormat.Line(previous), m.numberFormat.Line(current)
+	if m.notice != "" {
+		previous = m.notice
+	}
+	if m.macroPrompt != macroNone {
+		previous = m.macroPromptText()
+	}
+	if m.memoryPrompt != memoryNone {
+		previous = m.memoryPromptText()
+	}
+	return previous, current
+}
 
-	// Logo - match button grid width (4 buttons × 6 chars = 24)
-	b.WriteString(logoStyle.Width(24).Render("🪿 GOOSE 🪿"))
-	b.WriteString("\n\n")
+func (m model) renderLCD(g geometry) string {
+	// Display - width matches the button grid
+	displayWidth := g.gridWidth()
+	textWidth := m.lcdTextWidth(g)
 
-	// Display - width matches 4 buttons at 6 chars each = 24
-	displayWidth := 24
-	var combinedDisplay string
-	if m.previousDisplay != "" {
-		prev := previousDisplayStyle.Width(displayWidth - 4).Render(m.previousDisplay)
-		curr := displayStyle.Width(displayWidth - 4).Render(m.display)
-		combinedDisplay = lipgloss.JoinVertical(lipgloss.Right, prev, curr)
+	previous, current := m.lcdLines()
+	var top []string
+	if g.compact {
+		top = []string{m.renderAnnunciatorsBeside(previous, textWidth)}
 	} else {
-		empty := previousDisplayStyle.Width(displayWidth - 4).Render("")
-		curr := displayStyle.Width(displayWidth - 4).Render(m.display)
-		combinedDisplay = lipgloss.JoinVertical(lipgloss.Right, empty, curr)
+		top = []string{
+			m.styles.annunciators.Width(textWidth).Render(m.annunciatorLine(textWidth)),
+			m.styles.previousDisplay.Width(textWidth).Render(scrollWindow(previous, textWidth, m.scroll)),
+		}
 	}
-	b.WriteString(displayContainerStyle.Width(displayWidth).Render(combinedDisplay))
-	b.WriteString("\n\n")
+	curr := m.renderDisplayValue(current, textWidth)
+	combinedDisplay := lipgloss.JoinVertical(lipgloss.Right, append(top, curr)...)
+	return m.lcdContainer(g).Width(displayWidth).Render(combinedDisplay)
+}
 
-	// Button grid
-	for y, row := range m.buttons {
+func (m model) renderKeypad(g geometry) string {
+	var rows []string
+	for y, row := range m.layout.Rows {
 		var rowButtons []string
-		for x, val := range row {
-			var style lipgloss.Style
-
-			if val == "AC" {
-				style = acButtonStyle
-			} else if val == "=" {
-				style = equalsButtonStyle
-			} else if isOperator(val) {
-				style = operatorButtonStyle
-			} else if val == "+/-" || val == "%" || val == "." {
-				style = functionalButtonStyle
-			} else if val == "0" {
-				style = zeroButtonStyle
-			} else {
-				style = numberButtonStyle
-			}
+		for x, k := range row.Keys {
+			style := m.styles.classes[k.Class]
+			var mark marks
 
 			// Apply feedback
 			if m.pressedX == x && m.pressedY == y {
 				switch m.activationMethod {
 				case activationDirectKeyboard:
-					style = directKeyboardStyle.Copy().Width(style.GetWidth())
+					style, mark = m.styles.directKeyboard, m.styles.directKeyboardMarks
 				case activationNavigation:
-					style = pressedStyle.Copy().Width(style.GetWidth())
+					style, mark = m.styles.pressed, m.styles.pressedMarks
 				}
 			} else if m.cursorY == y && m.cursorX == x {
-				style = highlightStyle.Copy().Width(style.GetWidth())
+				style, mark = m.styles.highlight, m.styles.highlightMarks
 			}
 
-			// Wide 0 button - spans 2 button positions (6 + 6 = 12)
-			if val == "0" {
-				style = style.Copy().Width(12)
-			}
-
-			rowButtons = append(rowButtons, style.Render(val))
+			// Wide keys such as 0 span several button positions
+			width := g.buttonWidth * k.Span
+			rowButtons = append(rowButtons, style.Width(width).Height(g.buttonHeight).Render(mark.wrap(k.Label, width)))
 		}
-		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Left, rowButtons...))
-		b.WriteString("\n")
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Left, rowButtons...))
 	}
+	return strings.Join(rows, "\n")
+}
 
+func (m model) renderHelp(g geometry) string {
 	// Help - centered to match button grid width
-	b.WriteString("\n")
-	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#95A5A6")).
-		Width(24).
-		Align(lipgloss.Center).
-		Render("Press q or esc to quit")
-	b.WriteString(helpText)
-
-	return calculatorBodyStyle.Render(b.String())
+	width := g.gridWidth()
+	return m.styles.help.
+		Width(width).
+		Render(m.helpModel(width).ShortHelpView(m.keys.ShortHelp()))
 }
 
 func isSpecialFunc(s string) bool { return s == "AC" || s == "+/-" || s == "%" }
@@ -1,8 +1,11 @@
 package calculator_test
 
 import (
-	"github.com/dmisiuk/goose-tui-calculator/internal/calculator"
+	"strings"
 	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/calculator"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
 )
 
 func TestIntegrationExample(t *testing.T) {
@@ -63,3 +66,41 @@ func TestAudioWithCalculations(t *testing.T) {
 		}
 	}
 }
+
+func TestCustomLayoutIntegration(t *testing.T) {
+	l, err := keypad.Parse(`
+name = "minimal"
+
+[[row]]
+keys = [
+  { label = "C", action = "clear" },
+  { label = "×", action = "multiply" },
+  { action = "digit-6" },
+  { action = "digit-7" },
+]
+
+[[row]]
+keys = [{ label = "=", action = "equals", span = 4 }]
+`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	m := calculator.New(calculator.WithLayout(l))
+	output := m.View()
+	for _, label := range []string{"×", "C", "6", "7", "="} {
+		if !strings.Contains(output, label) {
+			t.Errorf("Expected %q in custom keypad", label)
+		}
+	}
+	if strings.Contains(output, "+/-") {
+		t.Errorf("Expected classic keys to be replaced")
+	}
+
+	for _, btn := range []string{"6", "x", "7", "="} {
+		m, _ = m.HandleButtonPress(btn)
+	}
+	if m.Display() != "42" {
+		t.Errorf("Expected 42, got %s", m.Display())
+	}
+}
@@ -1,7 +1,10 @@
 package calculator
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
 )
 
 func TestPreviousOperationDisplay(t *testing.T) {
@@ -130,3 +133,42 @@ func TestCalculationLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestNumberFormat(t *testing.T) {
+	m := New(WithNumberFormat(numfmt.Format{Decimals: 2, Rounding: numfmt.HalfUp, Notation: numfmt.Grouped}))
+	m = typeKeys(m, runes("2000/3=")...)
+
+	if m.display != "666.67" {
+		t.Errorf("Expected the result rounded to 2 places, got %q", m.display)
+	}
+	if m.previousDisplay != "2000 / 3 = 666.67" {
+		t.Errorf("Expected the rounded result in the previous operation, got %q", m.previousDisplay)
+	}
+
+	m = typeKeys(m, runes("*3=")...)
+	previous, current := m.lcdLines()
+	if current != "2,000.01" || previous != "666.67 x 3 = 2,000.01" {
+		t.Errorf("Expected grouped LCD lines, got %q and %q", previous, current)
+	}
+	if m.display != "2000.01" {
+		t.Errorf("Grouping should only change how the value is shown, got %q", m.display)
+	}
+	if !strings.Contains(m.View(), "2,000.01") {
+		t.Error("Expected the grouped value in the view")
+	}
+
+	m = New(WithNumberFormat(numfmt.Format{Decimals: numfmt.Auto, Notation: numfmt.Grouped}))
+	m = typeKeys(m, runes("1000*1000=")...)
+	if _, current := m.lcdLines(); current != "1,000,000" {
+		t.Errorf("Expected a grouped million rather than an exponent, got %q", current)
+	}
+}
+
+func TestSoundOff(t *testing.T) {
+	if _, cmd := New().handleButtonPress("1"); cmd == nil {
+		t.Error("Expected the terminal bell with sound on")
+	}
+	if _, cmd := New(WithSound(false)).handleButtonPress("1"); cmd != nil {
+		t.Error("Expected no terminal bell with sound off")
+	}
+}
@@ -137,16 +137,30 @@ func TestZeroButtonSpecialWidth(t *testing.T) {
 func TestMouseInteractionVisualFeedback(t *testing.T) {
 	m := New()
 
-	// Simulate mouse click on button at position (0, 2) which should be "AC" button
+	m.display = "5"
+
+	// Simulate mouse click on the "AC" button: inside the border and
+	// padding, below the logo and LCD
 	mouseMsg := tea.MouseMsg{
-		X:    0, // First column
-		Y:    2, // Row 0 of buttons (display takes up row 0-1)
-		Type: tea.MouseLeft,
+		X:      3,
+		Y:      10,
+		Action: tea.MouseActionPress,
+		Button: tea.MouseButtonLeft,
 	}
 
 	updatedModel, _ := m.Update(mouseMsg)
 	updated := updatedModel.(model)
 
+	if updated.pressedX != 0 || updated.pressedY != 0 || updated.activationMethod != activationNavigation {
+		t.Errorf("Expected pressed feedback on AC, got (%d, %d)", updated.pressedX, updated.pressedY)
+	}
+
+	// The button fires on release
+	mouseMsg.Action = tea.MouseActionRelease
+	mouseMsg.Button = tea.MouseButtonNone
+	updatedModel, _ = updated.Update(mouseMsg)
+	updated = updatedModel.(model)
+
 	// Verify the AC button was pressed (display should be reset to "0")
 	if updated.display != "0" {
 		t.Errorf("Expected display to show '0' after AC press, got '%s'", updated.display)
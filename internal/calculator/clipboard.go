@@ -0,0 +1,55 @@
+package calculator
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copiedMsg reports the result of writing to the clipboard.
+type copiedMsg struct {
+	err error
+}
+
+// WithClipboard sets where OSC 52 clipboard sequences are written. It
+// defaults to standard error so the sequences never interleave with frames
+// the renderer writes to standard output.
+func WithClipboard(w io.Writer) Option {
+	return func(m *model) {
+		m.clipboard = w
+	}
+}
+
+// copyCmd returns a command that puts s on the terminal's clipboard using
+// an OSC 52 escape sequence, wrapped for tmux or screen when needed.
+func (m model) copyCmd(s string) tea.Cmd {
+	w := m.clipboard
+	if w == nil {
+		w = os.Stderr
+	}
+	return func() tea.Msg {
+		seq := osc52.New(s)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+			seq = seq.Screen()
+		}
+		_, err := seq.WriteTo(w)
+		return copiedMsg{err: err}
+	}
+}
+
+// expression returns the full calculation for copying, falling back to the
+// display when there is no previous operation.
+func (m model) expression() string {
+	if m.previousDisplay == "" {
+		return m.display
+	}
+	if m.isOperand2 || m.operator == "" {
+		return m.previousDisplay
+	}
+	return m.previousDisplay + " " + m.display
+}
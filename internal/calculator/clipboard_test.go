@@ -0,0 +1,99 @@
+package calculator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func osc52Payload(t *testing.T, seq string) string {
+	t.Helper()
+	start := strings.Index(seq, "]52;c;")
+	end := strings.Index(seq, "\a")
+	if start < 0 || end < 0 {
+		t.Fatalf("Not an OSC 52 sequence: %q", seq)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(seq[start+len("]52;c;") : end])
+	if err != nil {
+		t.Fatalf("Invalid base64 in %q: %v", seq, err)
+	}
+	return string(decoded)
+}
+
+func TestCopyDisplay(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	var buf bytes.Buffer
+	m := typeKeys(New(WithClipboard(&buf)), runes("12+3=")...)
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updatedModel.(model)
+	if cmd == nil {
+		t.Fatalf("Expected a copy command")
+	}
+	msg := cmd()
+	if got := osc52Payload(t, buf.String()); got != "15" {
+		t.Errorf("Expected 15 on the clipboard, got %q", got)
+	}
+
+	updatedModel, _ = m.Update(msg)
+	m = updatedModel.(model)
+	if !strings.Contains(m.View(), "COPIED") {
+		t.Errorf("Expected COPIED confirmation on the LCD")
+	}
+}
+
+func TestCopyExpression(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	tests := []struct {
+		name     string
+		keys     string
+		expected string
+	}{
+		{"after equals", "12+3=", "12 + 3 = 15"},
+		{"operator pending", "12+", "12 +"},
+		{"second operand", "12+3", "12 + 3"},
+		{"no previous operation", "42", "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			m := typeKeys(New(WithClipboard(&buf)), runes(tt.keys)...)
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+			cmd()
+			if got := osc52Payload(t, buf.String()); got != tt.expected {
+				t.Errorf("Expected %q on the clipboard, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCopyInsideTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	var buf bytes.Buffer
+	m := New(WithClipboard(&buf))
+	m.copyCmd("7")()
+
+	if !strings.HasPrefix(buf.String(), "\x1bPtmux;") {
+		t.Errorf("Expected tmux passthrough, got %q", buf.String())
+	}
+}
+
+func TestNoticeExpires(t *testing.T) {
+	m, _ := New().showNotice("COPIED")
+	m.noticeTime = m.noticeTime.Add(-2 * noticeDuration)
+
+	updatedModel, _ := m.Update(tickMsg{})
+	m = updatedModel.(model)
+	if m.notice != "" {
+		t.Errorf("Expected notice to expire, got %q", m.notice)
+	}
+}
@@ -0,0 +1,119 @@
+package calculator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Button sizes in terminal cells.
+const (
+	defaultButtonWidth  = 6
+	defaultButtonHeight = 2
+	minButtonWidth      = 4
+	maxButtonWidth      = 12
+	maxButtonHeight     = 3
+)
+
+// geometry is the size of the calculator's parts for the current terminal.
+type geometry struct {
+	buttonWidth  int
+	buttonHeight int
+	columns      int  // keypad width in button positions
+	compact      bool // LCD and keypad only, without logo, help or padding
+	showTape     bool // the tape pane is shown and fits
+	tooSmall     bool // not even the compact layout fits
+}
+
+// gridWidth is the width of the keypad, which the logo, LCD and help match.
+func (g geometry) gridWidth() int {
+	return g.columns * g.buttonWidth
+}
+
+// geometry fits the calculator into the terminal size reported by the last
+// tea.WindowSizeMsg, falling back to the compact layout when the full one
+// does not fit. Until the size is known the default button size is used.
+func (m model) geometry() geometry {
+	g := geometry{
+		buttonWidth:  defaultButtonWidth,
+		buttonHeight: defaultButtonHeight,
+		columns:      m.layout.Columns(),
+		showTape:     m.showTape,
+	}
+	if m.width == 0 || m.height == 0 {
+		return g
+	}
+
+	rows := len(m.layout.Rows)
+	for _, compact := range []bool{false, true} {
+		g.compact = compact
+
+		// Width left after the frame, and the tape pane when it fits, is
+		// shared between the columns.
+		width := m.width - m.bodyStyle(g).GetHorizontalFrameSize()
+		g.showTape = m.showTape && width-tapePaneWidth >= g.columns*minButtonWidth
+		if g.showTape {
+			width -= tapePaneWidth
+		}
+		g.buttonWidth = min(width/g.columns, maxButtonWidth)
+		if g.buttonWidth < minButtonWidth {
+			continue
+		}
+
+		// Height left after the other sections goes to the button rows.
+		// Rendering one line per row measures everything else.
+		g.buttonHeight = 1
+		fixed := lipgloss.Height(m.renderBody(g)) - rows
+		g.buttonHeight = min((m.height-fixed)/rows, maxButtonHeight)
+		if g.buttonHeight >= 1 {
+			return g
+		}
+	}
+	g.tooSmall = true
+	return g
+}
+
+// renderTooSmall replaces the calculator when the terminal cannot fit it.
+func (m model) renderTooSmall() string {
+	return fmt.Sprintf("Terminal too small (%dx%d).\nPlease enlarge the window.", m.width, m.height)
+}
+
+// keypadOrigin returns the screen cell of the keypad's top-left corner,
+// measured from the sections rendered above it.
+func (m model) keypadOrigin(g geometry) (int, int) {
+	style := m.bodyStyle(g)
+	x := style.GetBorderLeftSize() + style.GetPaddingLeft()
+	y := style.GetBorderTopSize() + style.GetPaddingTop()
+	gap := strings.Count(m.sectionSeparator(g), "\n") - 1
+	for _, section := range m.sectionsAboveKeypad(g) {
+		y += lipgloss.Height(section) + gap
+	}
+	return x, y
+}
+
+// buttonAt returns the keypad position of the button covering screen cell
+// (col, row), taking wide keys into account.
+func (m model) buttonAt(col, row int) (int, int, bool) {
+	g := m.geometry()
+	if g.tooSmall {
+		return 0, 0, false
+	}
+	originX, originY := m.keypadOrigin(g)
+	if row < originY || col < originX {
+		return 0, 0, false
+	}
+	y := (row - originY) / g.buttonHeight
+	if y >= len(m.layout.Rows) {
+		return 0, 0, false
+	}
+	left := originX
+	for x, k := range m.layout.Rows[y].Keys {
+		right := left + k.Span*g.buttonWidth
+		if col < right {
+			return x, y, true
+		}
+		left = right
+	}
+	return 0, 0, false
+}
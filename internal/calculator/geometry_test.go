@@ -0,0 +1,100 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func resize(m model, width, height int) model {
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updatedModel.(model)
+}
+
+func TestGeometryDefaultsBeforeWindowSize(t *testing.T) {
+	g := New().geometry()
+	if g.buttonWidth != defaultButtonWidth || g.buttonHeight != defaultButtonHeight || g.compact || g.tooSmall {
+		t.Errorf("Expected default geometry, got %+v", g)
+	}
+	if g.gridWidth() != 24 {
+		t.Errorf("Expected 24 column grid, got %d", g.gridWidth())
+	}
+}
+
+func TestGeometryScalesWithTerminal(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		buttonWidth   int
+		buttonHeight  int
+		compact       bool
+	}{
+		{"standard terminal", 80, 24, maxButtonWidth, 2, false},
+		{"large terminal", 200, 60, maxButtonWidth, maxButtonHeight, false},
+		{"narrow terminal", 40, 30, 8, maxButtonHeight, false},
+		{"short terminal", 80, 19, maxButtonWidth, 1, false},
+		{"compact", 30, 14, 6, 2, true},
+		{"smallest compact", 22, 9, 4, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := resize(New(), tt.width, tt.height).geometry()
+			if g.tooSmall {
+				t.Fatalf("Expected calculator to fit %dx%d", tt.width, tt.height)
+			}
+			if g.buttonWidth != tt.buttonWidth || g.buttonHeight != tt.buttonHeight || g.compact != tt.compact {
+				t.Errorf("Expected %dx%d buttons (compact %v), got %+v",
+					tt.buttonWidth, tt.buttonHeight, tt.compact, g)
+			}
+		})
+	}
+}
+
+func TestViewFitsTerminal(t *testing.T) {
+	for width := 10; width <= 120; width += 3 {
+		for height := 5; height <= 45; height += 2 {
+			m := resize(New(WithTapePane()), width, height)
+			output := m.View()
+			if m.geometry().tooSmall {
+				continue
+			}
+			if w, h := lipgloss.Width(output), lipgloss.Height(output); w > width || h > height {
+				t.Errorf("%dx%d view rendered into a %dx%d terminal", w, h, width, height)
+			}
+		}
+	}
+}
+
+func TestCompactLayoutHidesLogoAndHelp(t *testing.T) {
+	output := resize(New(), 30, 14).View()
+	if strings.Contains(output, "GOOSE") || strings.Contains(output, "quit") {
+		t.Errorf("Expected compact layout without logo and help:\n%s", output)
+	}
+	if !strings.Contains(output, "AC") {
+		t.Errorf("Expected keypad in compact layout")
+	}
+}
+
+func TestTerminalTooSmall(t *testing.T) {
+	output := resize(New(), 15, 5).View()
+	if !strings.Contains(output, "Terminal too small") {
+		t.Errorf("Expected too small notice, got:\n%s", output)
+	}
+	if strings.Contains(output, "AC") {
+		t.Errorf("Expected no broken calculator render")
+	}
+}
+
+func TestTapePaneDroppedWhenNarrow(t *testing.T) {
+	g := resize(New(WithTapePane()), 40, 24).geometry()
+	if g.showTape {
+		t.Errorf("Expected tape pane to be hidden in a narrow terminal")
+	}
+	g = resize(New(WithTapePane()), 80, 24).geometry()
+	if !g.showTape {
+		t.Errorf("Expected tape pane to fit in 80 columns")
+	}
+}
@@ -0,0 +1,120 @@
+package calculator
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ShortHelp returns the bindings shown in the footer.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+// FullHelp returns every binding, grouped into the columns of the help
+// overlay.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Left, k.Right, k.RowStart, k.RowEnd, k.Top, k.Bottom, k.Enter},
+		k.actionHelp(),
+		{k.Undo, k.Redo, k.Review, k.Copy, k.CopyExpression, k.ScrollLeft, k.ScrollRight, k.Record, k.Play, k.Store, k.Recall, k.Variables},
+		{k.Print, k.Tape, k.Theme, k.Segments, k.KeypadMode, k.Settings, k.Help, k.Quit, k.Esc},
+	}
+}
+
+// actionHelp returns the bindings that press calculator buttons, with
+// the digits shown as one 0-9 entry while they have their default keys.
+func (k keyMap) actionHelp() []key.Binding {
+	digitsDefault := true
+	for d := '0'; d <= '9'; d++ {
+		if keys := k.Actions["digit-"+string(d)].Keys(); len(keys) != 1 || keys[0] != string(d) {
+			digitsDefault = false
+		}
+	}
+
+	var bindings []key.Binding
+	if digitsDefault {
+		bindings = append(bindings, key.NewBinding(key.WithKeys("0"), key.WithHelp("0-9", "digits")))
+	}
+	for _, id := range actionOrder {
+		if digitsDefault && strings.HasPrefix(id, "digit-") {
+			continue
+		}
+		bindings = append(bindings, k.Actions[id])
+	}
+	return bindings
+}
+
+// helpModel returns the help component drawn with the theme, at most
+// width columns wide.
+func (m model) helpModel(width int) help.Model {
+	h := help.New()
+	h.Width = width
+	h.Styles.ShortKey = m.styles.helpKey
+	h.Styles.ShortDesc = m.styles.helpDesc
+	h.Styles.ShortSeparator = m.styles.helpDesc
+	h.Styles.Ellipsis = m.styles.helpDesc
+	h.Styles.FullKey = m.styles.helpKey
+	h.Styles.FullDesc = m.styles.helpDesc
+	h.Styles.FullSeparator = m.styles.helpDesc
+	return h
+}
+
+// helpColumnGap separates the columns of the help overlay.
+const helpColumnGap = "    "
+
+// renderHelpOverlay lists every keyboard shortcut in place of the
+// calculator. Columns that do not fit side by side wrap onto more rows.
+func (m model) renderHelpOverlay() string {
+	style := m.styles.body
+	width := m.width - style.GetHorizontalFrameSize()
+	if m.width == 0 {
+		width = 1 << 16 // no size yet: one row of columns
+	}
+
+	h := m.helpModel(0)
+	h.FullSeparator = ""
+	var rows, row []string
+	rowWidth := 0
+	for _, group := range m.keys.FullHelp() {
+		column := h.FullHelpView([][]key.Binding{group})
+		w := lipgloss.Width(column)
+		if len(row) > 0 && rowWidth+len(helpColumnGap)+w > width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		if len(row) > 0 {
+			row = append(row, helpColumnGap)
+			rowWidth += len(helpColumnGap)
+		}
+		row = append(row, column)
+		rowWidth += w
+	}
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+
+	columns := strings.Join(rows, "\n\n")
+	w := lipgloss.Width(columns)
+	title := m.styles.logo.Width(w).Render("KEYBOARD SHORTCUTS")
+	footer := m.styles.help.Width(w).Render(m.keys.Help.Help().Key + " or esc to close")
+	box := style.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", columns, "", footer))
+	if m.width == 0 || m.height == 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// updateHelp handles keys while the help overlay is open: the help key or
+// esc closes it and quit still quits.
+func (m model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Help), key.Matches(msg, m.keys.Esc):
+		m.showHelp = false
+	case key.Matches(msg, m.keys.Quit):
+		m.isQuitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
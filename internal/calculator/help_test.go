@@ -0,0 +1,65 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFooterShowsShortHelp(t *testing.T) {
+	output := New().View()
+	if !strings.Contains(output, "? help • q quit") {
+		t.Errorf("Expected short help in the footer:\n%s", output)
+	}
+}
+
+func TestHelpOverlayListsEveryShortcut(t *testing.T) {
+	m := typeKeys(New(), runes("?")...)
+	if !m.showHelp {
+		t.Fatalf("Expected ? to open the help overlay")
+	}
+
+	output := m.View()
+	for _, group := range m.keys.FullHelp() {
+		for _, b := range group {
+			if !b.Enabled() {
+				continue
+			}
+			if !strings.Contains(output, b.Help().Key) || !strings.Contains(output, b.Help().Desc) {
+				t.Errorf("Expected %q %q in the help overlay", b.Help().Key, b.Help().Desc)
+			}
+		}
+	}
+	for _, direct := range []string{"0-9", "*/x", "all clear"} {
+		if !strings.Contains(output, direct) {
+			t.Errorf("Expected direct key %q in the help overlay", direct)
+		}
+	}
+}
+
+func TestHelpOverlayFitsNarrowTerminals(t *testing.T) {
+	m := resize(typeKeys(New(), runes("?")...), 60, 50)
+	for _, line := range strings.Split(m.View(), "\n") {
+		if w := len([]rune(line)); w > 60 {
+			t.Errorf("Line wider than the terminal (%d): %q", w, line)
+		}
+	}
+}
+
+func TestHelpOverlayKeys(t *testing.T) {
+	m := typeKeys(New(), runes("?5")...)
+	if m.display != "0" {
+		t.Errorf("Expected keys to be ignored under the overlay, got %s", m.display)
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showHelp || m.isQuitting {
+		t.Errorf("Expected esc to close the overlay without quitting")
+	}
+
+	m = typeKeys(m, runes("??")...)
+	if m.showHelp {
+		t.Errorf("Expected ? to toggle the overlay")
+	}
+}
@@ -0,0 +1,276 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
+)
+
+type keyMap struct {
+	Up             key.Binding
+	Down           key.Binding
+	Left           key.Binding
+	Right          key.Binding
+	RowStart       key.Binding
+	RowEnd         key.Binding
+	Top            key.Binding
+	Bottom         key.Binding
+	Enter          key.Binding
+	Quit           key.Binding
+	Esc            key.Binding
+	Print          key.Binding
+	Tape           key.Binding
+	Undo           key.Binding
+	Redo           key.Binding
+	Review         key.Binding
+	Copy           key.Binding
+	CopyExpression key.Binding
+	Theme          key.Binding
+	Segments       key.Binding
+	ScrollLeft     key.Binding
+	ScrollRight    key.Binding
+	Help           key.Binding
+	KeypadMode     key.Binding
+	Settings       key.Binding
+	Record         key.Binding
+	Play           key.Binding
+	Store          key.Binding
+	Recall         key.Binding
+	Variables      key.Binding
+
+	// Actions press calculator buttons directly, keyed by keypad action id
+	Actions map[string]key.Binding
+}
+
+// actionOrder lists the keypad actions in the order the help shows them.
+var actionOrder = []string{
+	"digit-0", "digit-1", "digit-2", "digit-3", "digit-4",
+	"digit-5", "digit-6", "digit-7", "digit-8", "digit-9",
+	"add", "subtract", "multiply", "divide", "decimal",
+	"equals", "percent", "negate", "clear", "clear-entry",
+}
+
+func defaultKeyMap() keyMap {
+	actions := map[string]key.Binding{
+		"add":      key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "add")),
+		"subtract": key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "subtract")),
+		"multiply": key.NewBinding(key.WithKeys("*", "x"), key.WithHelp("*/x", "multiply")),
+		"divide":   key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "divide")),
+		"decimal":  key.NewBinding(key.WithKeys("."), key.WithHelp(".", "decimal point")),
+		"equals":   key.NewBinding(key.WithKeys("="), key.WithHelp("=", "equals")),
+		"percent":  key.NewBinding(key.WithKeys("%"), key.WithHelp("%", "percent")),
+		"negate":   key.NewBinding(key.WithKeys("~"), key.WithHelp("~", "change sign")),
+		"clear":    key.NewBinding(key.WithKeys("c", "C"), key.WithHelp("c", "all clear")),
+		// Bound in keypad mode, or from the config file
+		"clear-entry": key.NewBinding(key.WithHelp("", "clear entry")),
+	}
+	for d := '0'; d <= '9'; d++ {
+		actions["digit-"+string(d)] = key.NewBinding(key.WithKeys(string(d)), key.WithHelp(string(d), "digit "+string(d)))
+	}
+
+	return keyMap{
+		Up:             key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move up")),
+		Down:           key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move down")),
+		Left:           key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "move left")),
+		Right:          key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "move right")),
+		RowStart:       key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "first in row")),
+		RowEnd:         key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "last in row")),
+		Top:            key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "top row")),
+		Bottom:         key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdn", "bottom row")),
+		Enter:          key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "press button")),
+		Quit:           key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		Esc:            key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
+		Print:          key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "toggle print mode")),
+		Tape:           key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "toggle tape pane")),
+		Undo:           key.NewBinding(key.WithKeys("ctrl+z"), key.WithHelp("ctrl+z", "undo")),
+		Redo:           key.NewBinding(key.WithKeys("ctrl+y"), key.WithHelp("ctrl+y", "redo")),
+		Review:         key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "check and correct")),
+		Copy:           key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy result")),
+		CopyExpression: key.NewBinding(key.WithKeys("Y"), key.WithHelp("Y", "copy calculation")),
+		Theme:          key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "next theme")),
+		Segments:       key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "toggle seven-segment LCD")),
+		ScrollLeft:     key.NewBinding(key.WithKeys("shift+left", "["), key.WithHelp("shift+←/[", "scroll LCD left")),
+		ScrollRight:    key.NewBinding(key.WithKeys("shift+right", "]"), key.WithHelp("shift+→/]", "scroll LCD right")),
+		Help:           key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		KeypadMode:     key.NewBinding(key.WithKeys("K"), key.WithHelp("K", "toggle keypad mode")),
+		Settings:       key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "settings")),
+		Record:         key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "record macro")),
+		Play:           key.NewBinding(key.WithKeys("@"), key.WithHelp("@", "play macro")),
+		Store:          key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "store in variable")),
+		Recall:         key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "recall variable")),
+		Variables:      key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "list variables")),
+		Actions:        actions,
+	}
+}
+
+// commands returns the app command bindings by their name in the config
+// file.
+func (k *keyMap) commands() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":              &k.Up,
+		"down":            &k.Down,
+		"left":            &k.Left,
+		"right":           &k.Right,
+		"row-start":       &k.RowStart,
+		"row-end":         &k.RowEnd,
+		"top":             &k.Top,
+		"bottom":          &k.Bottom,
+		"press":           &k.Enter,
+		"quit":            &k.Quit,
+		"escape":          &k.Esc,
+		"print":           &k.Print,
+		"tape":            &k.Tape,
+		"undo":            &k.Undo,
+		"redo":            &k.Redo,
+		"review":          &k.Review,
+		"copy":            &k.Copy,
+		"copy-expression": &k.CopyExpression,
+		"theme":           &k.Theme,
+		"segments":        &k.Segments,
+		"scroll-left":     &k.ScrollLeft,
+		"scroll-right":    &k.ScrollRight,
+		"help":            &k.Help,
+		"keypad-mode":     &k.KeypadMode,
+		"settings":        &k.Settings,
+		"record-macro":    &k.Record,
+		"play-macro":      &k.Play,
+		"store":           &k.Store,
+		"recall":          &k.Recall,
+		"variables":       &k.Variables,
+	}
+}
+
+// button returns the calculator button msg presses directly.
+func (k keyMap) button(msg tea.KeyMsg) (string, bool) {
+	for id, b := range k.Actions {
+		if key.Matches(msg, b) {
+			return keypad.Actions[id], true
+		}
+	}
+	return "", false
+}
+
+// bind replaces the keys of the named bindings with custom ones. An empty
+// list unbinds. Unknown names and keys bound twice are reported.
+func (k keyMap) bind(custom map[string][]string) (keyMap, error) {
+	k.Actions = maps.Clone(k.Actions)
+	commands := k.commands()
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		keys := custom[name]
+		if b, ok := commands[name]; ok {
+			*b = rebind(*b, keys)
+		} else if b, ok := k.Actions[name]; ok {
+			k.Actions[name] = rebind(b, keys)
+		} else {
+			errs = append(errs, fmt.Errorf("unknown key binding %q", name))
+		}
+	}
+	return k, errors.Join(append(errs, k.conflicts()...)...)
+}
+
+func rebind(b key.Binding, keys []string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), b.Help().Desc))
+}
+
+// conflicts reports every key that more than one binding uses.
+func (k keyMap) conflicts() []error {
+	owners := map[string][]string{}
+	add := func(name string, b key.Binding) {
+		for _, s := range b.Keys() {
+			if !slices.Contains(owners[s], name) {
+				owners[s] = append(owners[s], name)
+			}
+		}
+	}
+	for name, b := range k.commands() {
+		add(name, *b)
+	}
+	for id, b := range k.Actions {
+		add(id, b)
+	}
+
+	var errs []error
+	for _, s := range slices.Sorted(maps.Keys(owners)) {
+		if names := owners[s]; len(names) > 1 {
+			slices.Sort(names)
+			errs = append(errs, fmt.Errorf("key %q is bound to %s", s, strings.Join(names, " and ")))
+		}
+	}
+	return errs
+}
+
+// keypadCentric rebinds the keys for entering numbers on a numeric
+// keypad: Enter is =, Delete is CE and the keypad's decimal comma is the
+// decimal point. Space still presses the highlighted button.
+//
+// With Num Lock on, terminals send the keypad's keys as the characters
+// they show, and its Enter as enter, so Bubble Tea v1 reports them like
+// the main keyboard's and the other keys map as they are. The same goes
+// for shift+=, which arrives as +. Bubble Tea v1 cannot tell keypad keys
+// apart, and with Num Lock off it reports them as home, up, pgup and so
+// on, which keep moving the highlight.
+func (k keyMap) keypadCentric() keyMap {
+	k.Actions = maps.Clone(k.Actions)
+	k.Enter = key.NewBinding(key.WithKeys(" "), key.WithHelp("space", k.Enter.Help().Desc))
+	k.Actions["equals"] = key.NewBinding(key.WithKeys("=", "enter"), key.WithHelp("=/enter", "equals"))
+	k.Actions["clear-entry"] = key.NewBinding(key.WithKeys("delete"), key.WithHelp("del", "clear entry"))
+	k.Actions["decimal"] = key.NewBinding(key.WithKeys(".", ","), key.WithHelp("./,", "decimal point"))
+	return k
+}
+
+// keyMapFor returns the default keys, made keypad-centric in keypad mode,
+// with the custom bindings applied.
+func keyMapFor(keypadMode bool, custom map[string][]string) (keyMap, error) {
+	k := defaultKeyMap()
+	if keypadMode {
+		k = k.keypadCentric()
+	}
+	return k.bind(custom)
+}
+
+// CheckKeyBindings reports problems with custom key bindings: unknown
+// binding names and keys bound to more than one binding, in keypad mode
+// or not.
+func CheckKeyBindings(custom map[string][]string, keypadMode bool) error {
+	_, err := keyMapFor(keypadMode, custom)
+	return err
+}
+
+// WithKeyBindings replaces the default keys of the named bindings, as
+// checked by CheckKeyBindings. Bindings that do not pass the check are
+// ignored, with a notice on the LCD.
+func WithKeyBindings(custom map[string][]string) Option {
+	return func(m *model) {
+		m.keyBindings = custom
+	}
+}
+
+// WithKeypadMode starts the calculator in keypad mode.
+func WithKeypadMode() Option {
+	return func(m *model) {
+		m.keypadMode = true
+	}
+}
+
+// toggleKeypadMode switches keypad mode and names the new mode on the LCD.
+// The mode stays when the custom bindings conflict with the other mode.
+func (m model) toggleKeypadMode() (model, tea.Cmd) {
+	keys, err := keyMapFor(!m.keypadMode, m.keyBindings)
+	if err != nil {
+		return m.showNotice("KEY CONFLICT")
+	}
+	m.keys = keys
+	m.keypadMode = !m.keypadMode
+	if m.keypadMode {
+		return m.showNotice("KEYPAD MODE")
+	}
+	return m.showNotice("KEYBOARD MODE")
+}
@@ -0,0 +1,213 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDefaultKeyMapHasNoConflicts(t *testing.T) {
+	if errs := defaultKeyMap().conflicts(); len(errs) > 0 {
+		t.Errorf("Expected no conflicts, got %v", errs)
+	}
+	for _, id := range actionOrder {
+		if _, ok := defaultKeyMap().Actions[id]; !ok {
+			t.Errorf("Expected a default binding for %s", id)
+		}
+	}
+}
+
+func TestCustomKeyBindings(t *testing.T) {
+	m := New(WithKeyBindings(map[string][]string{
+		"quit":  {"ctrl+q"},
+		"clear": {"delete"},
+		"add":   {"+", "a"},
+	}))
+
+	m = typeKeys(m, runes("12a3=")...)
+	if m.display != "15" {
+		t.Errorf("Expected a to add, got %s", m.display)
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDelete})
+	if m.display != "0" {
+		t.Errorf("Expected delete to clear, got %s", m.display)
+	}
+
+	// The old keys are free again
+	m = typeKeys(m, runes("7cq")...)
+	if m.display != "7" || m.isQuitting {
+		t.Errorf("Expected c and q to do nothing, got %s (quitting %v)", m.display, m.isQuitting)
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	if cmd == nil {
+		t.Errorf("Expected ctrl+q to quit")
+	}
+}
+
+func TestCustomKeysShowInHelp(t *testing.T) {
+	m := New(WithKeyBindings(map[string][]string{"quit": {"ctrl+q"}, "digit-1": {"1", "!"}}))
+	if !strings.Contains(m.View(), "ctrl+q quit") {
+		t.Errorf("Expected the configured quit key in the footer:\n%s", m.View())
+	}
+
+	m = typeKeys(m, runes("?")...)
+	output := m.View()
+	if !strings.Contains(output, "1/!") || strings.Contains(output, "0-9") {
+		t.Errorf("Expected digits listed one by one once changed:\n%s", output)
+	}
+}
+
+func TestCheckKeyBindings(t *testing.T) {
+	if err := CheckKeyBindings(map[string][]string{"quit": {"Q"}, "clear": {"delete"}}, false); err != nil {
+		t.Errorf("Expected valid bindings, got %v", err)
+	}
+	if err := CheckKeyBindings(map[string][]string{"clear": {"delete"}}, true); err == nil {
+		t.Errorf("Expected delete to conflict with clear entry in keypad mode")
+	}
+
+	err := CheckKeyBindings(map[string][]string{
+		"copy":   {"c"},
+		"jump":   {"g"},
+		"divide": {"/", "d", "d"},
+	}, false)
+	if err == nil {
+		t.Fatalf("Expected errors")
+	}
+	for _, want := range []string{
+		`key "c" is bound to clear and copy`,
+		`unknown key binding "jump"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), `"d"`) {
+		t.Errorf("Expected a key repeated in one binding not to conflict:\n%v", err)
+	}
+}
+
+func TestInvalidKeyBindingsAreReported(t *testing.T) {
+	m := New(WithSound(false), WithKeyBindings(map[string][]string{"quit": {"Q"}, "copy": {"c"}}))
+	if m.notice != "KEYS IGNORED" || m.Init() == nil {
+		t.Errorf("Expected a notice that clears itself, got %q", m.notice)
+	}
+	m = typeKeys(m, runes("5c")...)
+	if m.display != "0" {
+		t.Errorf("Expected the default keys to stay, so c clears, got %q", m.display)
+	}
+	if New().Init() != nil {
+		t.Errorf("Expected nothing to start without a notice")
+	}
+}
+
+func TestUnboundKey(t *testing.T) {
+	m := New(WithKeyBindings(map[string][]string{"theme": {}}))
+	m = typeKeys(m, runes("t")...)
+	if m.theme.Name != "classic" {
+		t.Errorf("Expected an unbound command to do nothing")
+	}
+}
+
+func TestKeypadMode(t *testing.T) {
+	m := New(WithKeypadMode())
+	m.cursorX, m.cursorY = 0, 0 // AC
+
+	m = typeKeys(m, runes("12+3")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.display != "15" {
+		t.Errorf("Expected enter to be =, got %s", m.display)
+	}
+
+	m = typeKeys(m, runes("*4,5")...)
+	if m.display != "4.5" {
+		t.Errorf("Expected the keypad comma as a decimal point, got %s", m.display)
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDelete})
+	if m.display != "0" || m.operator != "x" || m.operand1 != "15" {
+		t.Errorf("Expected delete to clear only the entry, got %+v", m.state())
+	}
+	m = typeKeys(m, runes("2")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.display != "30" {
+		t.Errorf("Expected 15 x 2 = 30 after CE, got %s", m.display)
+	}
+
+	// Space still presses the highlighted button
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if m.display != "0" {
+		t.Errorf("Expected space to press AC, got %s", m.display)
+	}
+}
+
+func TestKeypadModeNumpadKeys(t *testing.T) {
+	// What a terminal sends for each keypad key with Num Lock on
+	numpad := map[string]string{
+		"0": "0", "1": "1", "5": "5", "9": "9",
+		"+": "+", "-": "-", "*": "x", "/": "/",
+		".": ".", ",": ".", "enter": "=",
+	}
+	k := defaultKeyMap().keypadCentric()
+	for sent, expected := range numpad {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(sent)}
+		if sent == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		if got, ok := k.button(msg); !ok || got != expected {
+			t.Errorf("Expected %q to press %q, got %q", sent, expected, got)
+		}
+	}
+}
+
+func TestToggleKeypadMode(t *testing.T) {
+	m := typeKeys(New(), runes("K")...)
+	if !m.keypadMode || m.notice != "KEYPAD MODE" {
+		t.Fatalf("Expected K to turn on keypad mode, got %v %q", m.keypadMode, m.notice)
+	}
+	if !strings.Contains(m.annunciatorLine(40), "KP") {
+		t.Errorf("Expected the KP annunciator")
+	}
+
+	m = typeKeys(m, runes("K")...)
+	if m.keypadMode {
+		t.Errorf("Expected K to turn keypad mode off")
+	}
+	m = typeKeys(m, runes("7")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.display != "0" || m.lastButton != "AC" {
+		t.Errorf("Expected enter to press the highlighted button again, got %s after %s", m.display, m.lastButton)
+	}
+
+	m = New(WithKeyBindings(map[string][]string{"clear": {"delete"}}))
+	m = typeKeys(m, runes("K")...)
+	if m.keypadMode || m.notice != "KEY CONFLICT" {
+		t.Errorf("Expected conflicting bindings to keep keyboard mode, got %v %q", m.keypadMode, m.notice)
+	}
+}
+
+func TestClearEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		buttons []string
+		display string
+		prev    string
+	}{
+		{"second operand", []string{"8", "x", "9", "CE", "3", "="}, "24", "8 x 3 = 24"},
+		{"before second operand", []string{"8", "x", "CE", "5", "="}, "40", "8 x 5 = 40"},
+		{"first operand", []string{"8", "CE", "6"}, "6", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			for _, btn := range tt.buttons {
+				updatedModel, _ := m.handleButtonPress(btn)
+				m = updatedModel.(model)
+			}
+			if m.display != tt.display || m.previousDisplay != tt.prev {
+				t.Errorf("Expected %q / %q, got %q / %q", tt.prev, tt.display, m.previousDisplay, m.display)
+			}
+		})
+	}
+}
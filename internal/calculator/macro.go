@@ -0,0 +1,145 @@
+package calculator
+
+import (
+	"errors"
+	"maps"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
+)
+
+// macroPrompt is what the next slot key does.
+type macroPrompt int
+
+const (
+	macroNone  macroPrompt = iota
+	macroStore             // store the recorded presses
+	macroPlay              // play a stored macro
+)
+
+// cancelPrompt leaves the macro and variable prompts without storing,
+// playing or recalling.
+var cancelPrompt = key.NewBinding(key.WithKeys("esc"))
+
+// WithMacros loads stored macros and saves newly recorded ones to the
+// macros file at path. An empty path keeps new macros for the session
+// only.
+func WithMacros(path string, macros config.Macros) Option {
+	return func(m *model) {
+		m.macrosPath = path
+		m.macros = macros
+	}
+}
+
+// actionFor returns the keypad action id that presses button.
+func actionFor(button string) (string, bool) {
+	for id, b := range keypad.Actions {
+		if b == button {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// record adds an entry, an action id or an entered value, to the macro
+// being recorded. The slice is copied, as undo states share it.
+func (m model) record(entry string) model {
+	if m.recording {
+		m.recorded = append(m.recorded[:len(m.recorded):len(m.recorded)], entry)
+	}
+	return m
+}
+
+// toggleRecording starts recording presses, or stops and asks for the
+// slot to store them in.
+func (m model) toggleRecording() (model, tea.Cmd) {
+	if !m.recording {
+		m.recording, m.recorded = true, nil
+		// Undoing back past the start must not bring back an older recording
+		m.undoStack, m.redoStack = withoutRecorded(m.undoStack), withoutRecorded(m.redoStack)
+		return m.showNotice("RECORDING")
+	}
+	m.recording = false
+	if len(m.recorded) == 0 {
+		return m.showNotice("NO PRESSES")
+	}
+	m.macroPrompt = macroStore
+	return m, nil
+}
+
+// updateMacroPrompt waits for a slot, 1 to 9, to store the recorded
+// presses in or to play.
+func (m model) updateMacroPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := m.macroPrompt
+	slot := msg.String()
+	switch {
+	case key.Matches(msg, cancelPrompt):
+		m.macroPrompt, m.recorded = macroNone, nil
+		return m, nil
+	case len(slot) != 1 || !strings.Contains(config.MacroSlots, slot):
+		return m, nil
+	}
+	m.macroPrompt = macroNone
+	if prompt == macroStore {
+		return m.storeMacro(slot)
+	}
+	return m.playMacro(slot)
+}
+
+// storeMacro puts the recorded presses in slot and saves the macros.
+func (m model) storeMacro(slot string) (tea.Model, tea.Cmd) {
+	m.macros = maps.Clone(m.macros)
+	if m.macros == nil {
+		m.macros = config.Macros{}
+	}
+	m.macros[slot], m.recorded = m.recorded, nil
+	if m.macrosPath == "" {
+		return m.showNotice("MACRO " + slot)
+	}
+	return m, m.saveMacrosCmd("MACRO " + slot)
+}
+
+// saveMacrosCmd returns a command that writes the macros to the macros
+// file and then shows notice.
+func (m model) saveMacrosCmd(notice string) tea.Cmd {
+	path, macros := m.macrosPath, m.macros
+	return func() tea.Msg {
+		if path == "" {
+			return savedMsg{err: errors.New("no macros file")}
+		}
+		return savedMsg{notice: notice, err: config.SaveMacros(path, macros)}
+	}
+}
+
+// playMacro presses the buttons and enters the values of the macro in
+// slot, starting from the value on the display. The whole macro is undone
+// in one step.
+func (m model) playMacro(slot string) (tea.Model, tea.Cmd) {
+	entries, ok := m.macros[slot]
+	if !ok {
+		return m.showNotice("NO MACRO " + slot)
+	}
+	m.undoStack = pushState(m.undoStack, m.state())
+	m.redoStack = nil
+	m.scroll = 0
+	for _, entry := range entries {
+		button, ok := keypad.Actions[entry]
+		if !ok {
+			m = m.enterValue(entry)
+			continue
+		}
+		m = m.record(entry).apply(button)
+	}
+	return m, nil
+}
+
+// macroPromptText asks for a slot on the LCD.
+func (m model) macroPromptText() string {
+	if m.macroPrompt == macroStore {
+		return "STORE IN 1-9"
+	}
+	return "PLAY 1-9"
+}
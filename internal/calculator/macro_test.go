@@ -0,0 +1,102 @@
+package calculator
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+)
+
+func TestRecordAndPlayMacro(t *testing.T) {
+	m := typeKeys(New(WithSound(false)), runes("100")...)
+	m = typeKeys(m, runes("m")...)
+	if !m.recording || !strings.Contains(m.annunciatorLine(40), "REC") {
+		t.Fatalf("expected recording with REC lit, got %q", m.annunciatorLine(40))
+	}
+	m = typeKeys(m, runes("*1.2=m")...)
+	if previous, _ := m.lcdLines(); previous != "STORE IN 1-9" {
+		t.Errorf("expected the slot prompt, got %q", previous)
+	}
+	m = typeKeys(m, runes("1")...)
+	expected := []string{"multiply", "digit-1", "decimal", "digit-2", "equals"}
+	if !reflect.DeepEqual(m.macros["1"], expected) || m.recording {
+		t.Fatalf("expected macro 1 to be %v, got %v", expected, m.macros["1"])
+	}
+	if m.display != "120" {
+		t.Errorf("expected recording to press the keys, got %q", m.display)
+	}
+
+	// Playing starts from the value on the display
+	m = typeKeys(m, runes("50@1")...)
+	if m.display != "60" || m.previousDisplay != "50 x 1.2 = 60" {
+		t.Errorf("expected 50 x 1.2 = 60, got %q and %q", m.display, m.previousDisplay)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if m.display != "50" {
+		t.Errorf("expected undo to take back the whole macro, got %q", m.display)
+	}
+}
+
+func TestRecordingFollowsUndoAndEnteredValues(t *testing.T) {
+	m := typeKeys(New(WithSound(false)), runes("m+3")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = typeKeys(m, runes("4=m1")...)
+	if expected := []string{"add", "digit-4", "equals"}; !reflect.DeepEqual(m.macros["1"], expected) {
+		t.Errorf("expected the undone press to be left out, got %v", m.macros["1"])
+	}
+	if m = typeKeys(m, runes("c10@1")...); m.display != "14" {
+		t.Errorf("expected 10 + 4 = 14, got %q", m.display)
+	}
+
+	m = typeKeys(m, runes("m*2")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyCtrlZ}, tea.KeyMsg{Type: tea.KeyCtrlY})
+	m = typeKeys(m, pasteMsg("2.5"))
+	m = typeKeys(m, runes("=m2")...)
+	if expected := []string{"multiply", "digit-2", "2.5", "equals"}; !reflect.DeepEqual(m.macros["2"], expected) {
+		t.Errorf("expected redo to restore the press and the pasted value to be kept, got %v", m.macros["2"])
+	}
+	if m = typeKeys(m, runes("c4@2")...); m.display != "10" {
+		t.Errorf("expected 4 x 2.5 = 10, got %q", m.display)
+	}
+}
+
+func TestMacroPromptCancelAndEmptySlot(t *testing.T) {
+	m := typeKeys(New(WithSound(false)), runes("m5m")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.macroPrompt != macroNone || len(m.macros) != 0 || m.isQuitting {
+		t.Errorf("expected esc to discard the recording, got %v", m.macros)
+	}
+
+	m = typeKeys(m, runes("@x3")...)
+	if m.notice != "NO MACRO 3" || m.display != "5" {
+		t.Errorf("expected the prompt to wait for a slot and report it empty, got %q, %q", m.notice, m.display)
+	}
+
+	m = typeKeys(m, runes("mm")...)
+	if m.notice != "NO PRESSES" || m.macroPrompt != macroNone {
+		t.Errorf("expected an empty recording to be dropped, got %q", m.notice)
+	}
+}
+
+func TestMacroIsSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macros.toml")
+	m := typeKeys(New(WithSound(false), WithMacros(path, config.Macros{"2": {"negate"}})), runes("m+1=m4")...)
+	msg := m.saveMacrosCmd("MACRO 4")().(savedMsg)
+	if msg.err != nil {
+		t.Fatalf("saving macros failed: %v", msg.err)
+	}
+	if updated, _ := m.Update(msg); updated.(model).notice != "MACRO 4" {
+		t.Errorf("expected the slot as the notice, got %q", updated.(model).notice)
+	}
+	loaded, err := config.LoadMacros(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := config.Macros{"2": {"negate"}, "4": {"add", "digit-1", "equals"}}
+	if !reflect.DeepEqual(loaded, expected) {
+		t.Errorf("expected %v, got %v", expected, loaded)
+	}
+}
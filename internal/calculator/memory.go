@@ -0,0 +1,154 @@
+package calculator
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+)
+
+// memoryPrompt is what the next letter key does.
+type memoryPrompt int
+
+const (
+	memoryNone   memoryPrompt = iota
+	memoryStore               // store the display in a variable
+	memoryRecall              // enter the value of a variable
+)
+
+// WithDefinitions makes the variables and functions of env available to
+// pasted expressions and the recall key, and saves variables stored with
+// the store key to the definitions file at path. An empty path keeps
+// them for the session only.
+func WithDefinitions(path string, env expr.Env) Option {
+	return func(m *model) {
+		m.definitionsPath = path
+		m.definitions = expr.Env{Vars: env.Vars, Funcs: env.Funcs}
+	}
+}
+
+// env returns the settings and definitions expressions are evaluated
+// with.
+func (m model) env() expr.Env {
+	env := m.definitions
+	env.Angle = m.angle
+	return env
+}
+
+// updateMemoryPrompt waits for a letter naming the variable to store the
+// display in or to recall. Letters are upper case, as in STO A.
+func (m model) updateMemoryPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := m.memoryPrompt
+	name := strings.ToUpper(msg.String())
+	switch {
+	case key.Matches(msg, cancelPrompt):
+		m.memoryPrompt = memoryNone
+		return m, nil
+	case len(name) != 1 || name[0] < 'A' || name[0] > 'Z':
+		return m, nil
+	}
+	m.memoryPrompt = memoryNone
+	if prompt == memoryStore {
+		return m.store(name)
+	}
+	return m.recall(name)
+}
+
+// store puts the value on the display in the variable name and saves the
+// definitions.
+func (m model) store(name string) (tea.Model, tea.Cmd) {
+	v, err := strconv.ParseFloat(m.display, 64)
+	if m.isError || err != nil {
+		return m.showNotice("NOTHING TO STORE")
+	}
+	m.definitions, _, err = m.definitions.Define(expr.Definition{Name: name, Body: strconv.FormatFloat(v, 'g', -1, 64)})
+	if err != nil {
+		return m.showNotice("STO FAILED")
+	}
+	if m.definitionsPath == "" {
+		return m.showNotice("STO " + name)
+	}
+	return m, m.saveDefinitionsCmd("STO " + name)
+}
+
+// saveDefinitionsCmd returns a command that writes the variables and
+// functions to the definitions file and then shows notice.
+func (m model) saveDefinitionsCmd(notice string) tea.Cmd {
+	path, env := m.definitionsPath, m.definitions
+	return func() tea.Msg {
+		if path == "" {
+			return savedMsg{err: errors.New("no definitions file")}
+		}
+		return savedMsg{notice: notice, err: config.SaveDefinitions(path, env)}
+	}
+}
+
+// recall enters the value of the variable name as if it were pasted.
+func (m model) recall(name string) (tea.Model, tea.Cmd) {
+	if _, ok := m.definitions.Vars[name]; !ok {
+		return m.showNotice("NO " + name)
+	}
+	return m.paste(name)
+}
+
+// memoryPromptText asks for a variable on the LCD.
+func (m model) memoryPromptText() string {
+	if m.memoryPrompt == memoryStore {
+		return "STO A-Z"
+	}
+	return "RCL A-Z"
+}
+
+// memoryLines lists the variables, then the functions, by name.
+func (m model) memoryLines() []string {
+	var lines []string
+	for _, name := range slices.Sorted(maps.Keys(m.definitions.Vars)) {
+		lines = append(lines, name+" = "+m.numberFormat.Display(m.numberFormat.Result(m.definitions.Vars[name])))
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.definitions.Funcs)) {
+		lines = append(lines, m.definitions.Funcs[name].String())
+	}
+	return lines
+}
+
+// updateMemory handles keys while the variables are listed: the
+// variables key or esc closes the list and quit still quits.
+func (m model) updateMemory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Variables), key.Matches(msg, m.keys.Esc):
+		m.showMemory = false
+	case key.Matches(msg, m.keys.Quit):
+		m.isQuitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderMemoryOverlay lists the variables and functions in place of the
+// calculator.
+func (m model) renderMemoryOverlay() string {
+	lines := m.memoryLines()
+	if len(lines) == 0 {
+		lines = []string{
+			"No variables or functions yet.",
+			"Store the display with " + m.keys.Store.Help().Key + " and a letter,",
+			"or define functions in calculator repl.",
+		}
+	}
+	items := m.styles.helpDesc.Render(strings.Join(lines, "\n"))
+	footer := m.keys.Variables.Help().Key + " or esc to close"
+	w := max(lipgloss.Width(items), lipgloss.Width(footer))
+	title := m.styles.logo.Width(w).Render("VARIABLES")
+	box := m.styles.body.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", items, "", m.styles.help.Width(w).Render(footer)))
+	if m.width == 0 || m.height == 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
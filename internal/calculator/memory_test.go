@@ -0,0 +1,88 @@
+package calculator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+)
+
+func TestStoreAndRecall(t *testing.T) {
+	m := typeKeys(New(WithSound(false)), runes("6*7=S")...)
+	if previous, _ := m.lcdLines(); previous != "STO A-Z" {
+		t.Errorf("expected the variable prompt, got %q", previous)
+	}
+	if strings.Contains(m.annunciatorLine(40), "M") {
+		t.Errorf("expected M to be dark before a value is stored")
+	}
+	m = typeKeys(m, runes("a")...)
+	if m.definitions.Vars["A"] != 42 || m.notice != "STO A" {
+		t.Fatalf("expected A = 42, got %v and notice %q", m.definitions.Vars, m.notice)
+	}
+	if got := strings.TrimSpace(m.annunciatorLine(40)); got != "M" {
+		t.Errorf("expected M to light once a value is stored, got %q", got)
+	}
+
+	m = typeKeys(m, runes("c2+RA=")...)
+	if m.display != "44" {
+		t.Errorf("expected 2 + A = 44, got %q", m.display)
+	}
+	m = typeKeys(m, runes("Rb")...)
+	if m.notice != "NO B" || m.display != "44" {
+		t.Errorf("expected recalling an empty variable to be refused, got %q, %q", m.notice, m.display)
+	}
+	m = typeKeys(m, runes("S")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.memoryPrompt != memoryNone || m.isQuitting || len(m.definitions.Vars) != 1 {
+		t.Errorf("expected esc to cancel the prompt")
+	}
+}
+
+func TestDefinitionsInPastedExpressions(t *testing.T) {
+	env := expr.Env{}
+	d, _, _ := expr.ParseDefinition("vat(x) = x * 1.2")
+	env, _, _ = env.Define(d)
+	m := New(WithSound(false), WithDefinitions("", env))
+	m = typeKeys(m, pasteMsg("vat(250)"))
+	if m.display != "300" {
+		t.Errorf("expected vat(250) = 300, got %q", m.display)
+	}
+}
+
+func TestStoreIsSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "definitions.toml")
+	m := typeKeys(New(WithSound(false), WithDefinitions(path, expr.Env{})), runes("12.5Sr")...)
+	msg := m.saveDefinitionsCmd("STO R")().(savedMsg)
+	if msg.err != nil {
+		t.Fatalf("saving definitions failed: %v", msg.err)
+	}
+	if updated, _ := m.Update(msg); updated.(model).notice != "STO R" {
+		t.Errorf("expected the variable as the notice, got %q", updated.(model).notice)
+	}
+	env, err := config.LoadDefinitions(path, expr.Env{})
+	if err != nil || env.Vars["R"] != 12.5 {
+		t.Errorf("expected R = 12.5 to be saved, got %v, %v", env.Vars, err)
+	}
+}
+
+func TestMemoryOverlay(t *testing.T) {
+	m := typeKeys(New(WithSound(false)), runes("v")...)
+	if !strings.Contains(m.View(), "No variables or functions yet") {
+		t.Errorf("expected an empty list, got:\n%s", m.View())
+	}
+	m = typeKeys(m, runes("v9SB")...)
+	d, _, _ := expr.ParseDefinition("avg(a, b) = (a + b) / 2")
+	m.definitions, _, _ = m.definitions.Define(d)
+	m = typeKeys(m, runes("v")...)
+	view := m.View()
+	if !strings.Contains(view, "VARIABLES") || !strings.Contains(view, "B = 9") || !strings.Contains(view, "avg(a, b) = (a + b) / 2") {
+		t.Errorf("expected B and avg to be listed, got:\n%s", view)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showMemory || m.isQuitting {
+		t.Errorf("expected esc to close the list")
+	}
+}
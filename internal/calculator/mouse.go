@@ -0,0 +1,56 @@
+package calculator
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateMouse gives the keypad GUI button semantics: hovering moves the
+// cursor highlight, pressing shows the pressed style while the button is
+// held, and the button fires on release only if the pointer is still over
+// the button that was pressed.
+func (m model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	x, y, over := m.buttonAt(msg.X, msg.Y)
+
+	switch msg.Action {
+	case tea.MouseActionMotion:
+		if over {
+			m = m.moveCursorTo(x, y)
+		}
+		if m.mouseDown {
+			m = m.showMouseDown(over && x == m.mouseDownX && y == m.mouseDownY)
+		}
+	case tea.MouseActionPress:
+		if msg.Button != tea.MouseButtonLeft || !over {
+			return m, nil
+		}
+		m = m.moveCursorTo(x, y)
+		m.mouseDown = true
+		m.mouseDownX, m.mouseDownY = x, y
+		m = m.showMouseDown(true)
+	case tea.MouseActionRelease:
+		if !m.mouseDown {
+			return m, nil
+		}
+		m.mouseDown = false
+		if over && x == m.mouseDownX && y == m.mouseDownY {
+			return m.press(m.layout.Key(x, y).Button(), x, y, activationNavigation)
+		}
+		m = m.showMouseDown(false)
+	}
+	return m, nil
+}
+
+// showMouseDown shows or clears the pressed style on the held button.
+func (m model) showMouseDown(pressed bool) model {
+	if !pressed {
+		m.pressedX, m.pressedY = -1, -1
+		m.activationMethod = activationNone
+		return m
+	}
+	m.pressedX, m.pressedY = m.mouseDownX, m.mouseDownY
+	m.activationMethod = activationNavigation
+	m.activationStartTime = time.Now()
+	return m
+}
@@ -0,0 +1,201 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func mouse(m model, x, y int, action tea.MouseAction, button tea.MouseButton) model {
+	updatedModel, _ := m.Update(tea.MouseMsg{X: x, Y: y, Action: action, Button: button})
+	return updatedModel.(model)
+}
+
+// click presses and releases the left button at (x, y).
+func click(m model, x, y int) model {
+	m = mouse(m, x, y, tea.MouseActionPress, tea.MouseButtonLeft)
+	return mouse(m, x, y, tea.MouseActionRelease, tea.MouseButtonNone)
+}
+
+func TestClickEveryButtonDefaultLayout(t *testing.T) {
+	// Default layout: border and padding put the keypad at column 3; the
+	// logo, a blank line, the 5-line LCD and another blank line put it at
+	// row 10. Buttons are 6x2 and the 0 key is 12 wide.
+	tests := []struct {
+		x, y   int
+		button string
+	}{
+		{3, 10, "AC"}, {8, 11, "AC"}, {9, 10, "+/-"}, {15, 10, "%"}, {21, 10, "/"}, {26, 11, "/"},
+		{3, 12, "7"}, {9, 12, "8"}, {15, 12, "9"}, {21, 13, "x"},
+		{3, 14, "4"}, {9, 14, "5"}, {15, 15, "6"}, {21, 14, "-"},
+		{3, 16, "1"}, {9, 16, "2"}, {15, 17, "3"}, {21, 16, "+"},
+		{3, 18, "0"}, {14, 19, "0"}, {15, 18, "."}, {21, 18, "="}, {26, 19, "="},
+	}
+
+	for _, tt := range tests {
+		x, y, ok := New().buttonAt(tt.x, tt.y)
+		if !ok {
+			t.Errorf("Expected a button at (%d, %d)", tt.x, tt.y)
+			continue
+		}
+		if got := New().layout.Key(x, y).Button(); got != tt.button {
+			t.Errorf("Button at (%d, %d) = %q, expected %q", tt.x, tt.y, got, tt.button)
+		}
+	}
+}
+
+func TestClickOutsideButtons(t *testing.T) {
+	points := [][2]int{
+		{0, 10},  // border
+		{2, 13},  // padding
+		{10, 5},  // LCD
+		{10, 2},  // logo
+		{27, 10}, // right of the keypad
+		{10, 20}, // below the keypad
+	}
+	for _, p := range points {
+		if x, y, ok := New().buttonAt(p[0], p[1]); ok {
+			t.Errorf("Expected no button at (%d, %d), got (%d, %d)", p[0], p[1], x, y)
+		}
+	}
+}
+
+// labelPosition finds where label is rendered on the keypad by scanning
+// the view from the bottom, so LCD digits are not matched.
+func labelPosition(t *testing.T, view, label string) (int, int) {
+	t.Helper()
+	lines := strings.Split(view, "\n")
+	for row := len(lines) - 1; row >= 0; row-- {
+		if i := strings.Index(lines[row], " "+label+" "); i >= 0 {
+			return lipgloss.Width(lines[row][:i]) + 1, row
+		}
+	}
+	t.Fatalf("Label %q not found in view", label)
+	return 0, 0
+}
+
+func TestClickEveryButtonByRenderedPosition(t *testing.T) {
+	sizes := [][2]int{{0, 0}, {80, 24}, {40, 18}, {30, 14}, {22, 9}, {120, 40}}
+	for _, size := range sizes {
+		m := resize(New(), size[0], size[1])
+		view := m.View()
+		for y, row := range m.layout.Rows {
+			for x, k := range row.Keys {
+				val := k.Label
+				col, line := labelPosition(t, view, val)
+				clicked := click(m, col, line)
+				if clicked.pressedX != x || clicked.pressedY != y {
+					t.Errorf("%dx%d: clicking %q at (%d, %d) pressed (%d, %d)",
+						size[0], size[1], val, col, line, clicked.pressedX, clicked.pressedY)
+				}
+			}
+		}
+	}
+}
+
+func TestClickPressesButtons(t *testing.T) {
+	m := New()
+	m = click(m, 9, 12)  // 8
+	m = click(m, 21, 16) // +
+	m = click(m, 3, 18)  // 0 (wide key, left half)
+	m = click(m, 14, 18) // 0 (wide key, right half)
+	m = click(m, 21, 18) // =
+	if m.display != "8" {
+		t.Errorf("Expected 8 + 00 = 8, got %s", m.display)
+	}
+	if m.previousDisplay != "8 + 0 = 8" {
+		t.Errorf("Unexpected previous display %q", m.previousDisplay)
+	}
+}
+
+func TestOnlyLeftPressActivates(t *testing.T) {
+	m := New()
+	for _, msg := range []tea.MouseMsg{
+		{X: 9, Y: 12, Action: tea.MouseActionPress, Button: tea.MouseButtonRight},
+		{X: 9, Y: 12, Action: tea.MouseActionMotion},
+		{X: 9, Y: 12, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp},
+	} {
+		updatedModel, _ := m.Update(msg)
+		m = updatedModel.(model)
+	}
+	if m.display != "0" {
+		t.Errorf("Expected no button press, got display %s", m.display)
+	}
+}
+
+func TestHoverMovesCursor(t *testing.T) {
+	m := mouse(New(), 15, 14, tea.MouseActionMotion, tea.MouseButtonNone) // 6
+	if m.cursorX != 2 || m.cursorY != 2 {
+		t.Errorf("Expected cursor on 6 at (2, 2), got (%d, %d)", m.cursorX, m.cursorY)
+	}
+
+	// Leaving the keypad keeps the last highlight
+	m = mouse(m, 10, 5, tea.MouseActionMotion, tea.MouseButtonNone)
+	if m.cursorX != 2 || m.cursorY != 2 {
+		t.Errorf("Expected cursor to stay at (2, 2), got (%d, %d)", m.cursorX, m.cursorY)
+	}
+	if m.display != "0" {
+		t.Errorf("Expected hovering not to press anything, got %s", m.display)
+	}
+}
+
+func TestPressedStyleWhileHeld(t *testing.T) {
+	m := mouse(New(), 9, 12, tea.MouseActionPress, tea.MouseButtonLeft) // 8
+	if m.display != "0" {
+		t.Errorf("Expected press not to fire before release, got %s", m.display)
+	}
+	if m.pressedX != 1 || m.pressedY != 1 || m.activationMethod != activationNavigation {
+		t.Errorf("Expected pressed style on 8, got (%d, %d)", m.pressedX, m.pressedY)
+	}
+
+	// The feedback timer must not clear a button that is still held
+	m.activationStartTime = m.activationStartTime.Add(-time.Second)
+	updatedModel, _ := m.Update(tickMsg{})
+	m = updatedModel.(model)
+	if m.pressedX != 1 || m.pressedY != 1 {
+		t.Errorf("Expected pressed style to stay while held")
+	}
+
+	m = mouse(m, 9, 12, tea.MouseActionRelease, tea.MouseButtonNone)
+	if m.display != "8" {
+		t.Errorf("Expected 8 on release, got %s", m.display)
+	}
+}
+
+func TestReleaseElsewhereCancels(t *testing.T) {
+	m := mouse(New(), 9, 12, tea.MouseActionPress, tea.MouseButtonLeft) // 8
+
+	// Dragging onto 9 releases the pressed style and moves the highlight
+	m = mouse(m, 15, 12, tea.MouseActionMotion, tea.MouseButtonLeft)
+	if m.pressedX != -1 || m.activationMethod != activationNone {
+		t.Errorf("Expected pressed style to clear when dragged off, got (%d, %d)", m.pressedX, m.pressedY)
+	}
+	if m.cursorX != 2 || m.cursorY != 1 {
+		t.Errorf("Expected highlight on 9, got (%d, %d)", m.cursorX, m.cursorY)
+	}
+
+	// Dragging back shows it again
+	m = mouse(m, 9, 12, tea.MouseActionMotion, tea.MouseButtonLeft)
+	if m.pressedX != 1 || m.pressedY != 1 {
+		t.Errorf("Expected pressed style when dragged back, got (%d, %d)", m.pressedX, m.pressedY)
+	}
+
+	m = mouse(m, 15, 12, tea.MouseActionMotion, tea.MouseButtonLeft)
+	m = mouse(m, 15, 12, tea.MouseActionRelease, tea.MouseButtonNone)
+	if m.display != "0" {
+		t.Errorf("Expected release over another button to cancel, got %s", m.display)
+	}
+	if m.mouseDown {
+		t.Errorf("Expected button to be released")
+	}
+}
+
+func TestReleaseWithoutPressIsIgnored(t *testing.T) {
+	m := mouse(New(), 9, 12, tea.MouseActionRelease, tea.MouseButtonNone)
+	if m.display != "0" {
+		t.Errorf("Expected stray release to do nothing, got %s", m.display)
+	}
+}
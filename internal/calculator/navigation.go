@@ -0,0 +1,73 @@
+package calculator
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WithWrap makes keyboard navigation wrap around at the edges of the
+// keypad.
+func WithWrap() Option {
+	return func(m *model) {
+		m.wrap = true
+	}
+}
+
+// moveCursorTo highlights key x of row y and remembers the grid column it
+// starts at, so moving up and down stays in that column across wide keys.
+func (m model) moveCursorTo(x, y int) model {
+	m.cursorX, m.cursorY = x, y
+	m.cursorCol = m.layout.Column(x, y)
+	return m
+}
+
+// moveToRow highlights the key of row y under the remembered column.
+func (m model) moveToRow(y int) model {
+	m.cursorY = y
+	m.cursorX = m.layout.KeyAt(m.cursorCol, y)
+	return m
+}
+
+// navigate moves the highlight for a navigation key. Arrows stop at the
+// edges unless wrap-around is on; home and end jump along the row, page up
+// and page down to the top and bottom rows.
+func (m model) navigate(msg tea.KeyMsg) model {
+	lastRow := len(m.layout.Rows) - 1
+	lastKey := len(m.layout.Rows[m.cursorY].Keys) - 1
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cursorY > 0 {
+			return m.moveToRow(m.cursorY - 1)
+		} else if m.wrap {
+			return m.moveToRow(lastRow)
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursorY < lastRow {
+			return m.moveToRow(m.cursorY + 1)
+		} else if m.wrap {
+			return m.moveToRow(0)
+		}
+	case key.Matches(msg, m.keys.Left):
+		if m.cursorX > 0 {
+			return m.moveCursorTo(m.cursorX-1, m.cursorY)
+		} else if m.wrap {
+			return m.moveCursorTo(lastKey, m.cursorY)
+		}
+	case key.Matches(msg, m.keys.Right):
+		if m.cursorX < lastKey {
+			return m.moveCursorTo(m.cursorX+1, m.cursorY)
+		} else if m.wrap {
+			return m.moveCursorTo(0, m.cursorY)
+		}
+	case key.Matches(msg, m.keys.RowStart):
+		return m.moveCursorTo(0, m.cursorY)
+	case key.Matches(msg, m.keys.RowEnd):
+		return m.moveCursorTo(lastKey, m.cursorY)
+	case key.Matches(msg, m.keys.Top):
+		return m.moveToRow(0)
+	case key.Matches(msg, m.keys.Bottom):
+		return m.moveToRow(lastRow)
+	}
+	return m
+}
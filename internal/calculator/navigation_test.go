@@ -0,0 +1,168 @@
+package calculator
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
+)
+
+var (
+	upKey       = tea.KeyMsg{Type: tea.KeyUp}
+	downKey     = tea.KeyMsg{Type: tea.KeyDown}
+	leftKey     = tea.KeyMsg{Type: tea.KeyLeft}
+	rightKey    = tea.KeyMsg{Type: tea.KeyRight}
+	homeKey     = tea.KeyMsg{Type: tea.KeyHome}
+	endKey      = tea.KeyMsg{Type: tea.KeyEnd}
+	pageUpKey   = tea.KeyMsg{Type: tea.KeyPgUp}
+	pageDownKey = tea.KeyMsg{Type: tea.KeyPgDown}
+)
+
+// cursorOn puts the cursor on the key labelled label.
+func cursorOn(t *testing.T, m model, label string) model {
+	t.Helper()
+	for y, row := range m.layout.Rows {
+		for x, k := range row.Keys {
+			if k.Label == label {
+				return m.moveCursorTo(x, y)
+			}
+		}
+	}
+	t.Fatalf("No key %q", label)
+	return m
+}
+
+func cursorLabel(m model) string {
+	return m.layout.Key(m.cursorX, m.cursorY).Label
+}
+
+// navigationCase lists where each arrow key leads from a key.
+type navigationCase struct {
+	from                  string
+	up, down, left, right string
+}
+
+func checkNavigation(t *testing.T, m model, tests []navigationCase) {
+	t.Helper()
+	for _, tt := range tests {
+		for _, move := range []struct {
+			key      tea.KeyMsg
+			expected string
+		}{{upKey, tt.up}, {downKey, tt.down}, {leftKey, tt.left}, {rightKey, tt.right}} {
+			got := cursorLabel(typeKeys(cursorOn(t, m, tt.from), move.key))
+			if got != move.expected {
+				t.Errorf("%s from %q: expected %q, got %q", move.key, tt.from, move.expected, got)
+			}
+		}
+	}
+}
+
+func TestNavigationEveryCell(t *testing.T) {
+	checkNavigation(t, New(), []navigationCase{
+		{"AC", "AC", "7", "AC", "+/-"},
+		{"+/-", "+/-", "8", "AC", "%"},
+		{"%", "%", "9", "+/-", "/"},
+		{"/", "/", "x", "%", "/"},
+		{"7", "AC", "4", "7", "8"},
+		{"8", "+/-", "5", "7", "9"},
+		{"9", "%", "6", "8", "x"},
+		{"x", "/", "-", "9", "x"},
+		{"4", "7", "1", "4", "5"},
+		{"5", "8", "2", "4", "6"},
+		{"6", "9", "3", "5", "-"},
+		{"-", "x", "+", "6", "-"},
+		{"1", "4", "0", "1", "2"},
+		{"2", "5", "0", "1", "3"},
+		{"3", "6", ".", "2", "+"},
+		{"+", "-", "=", "3", "+"},
+		{"0", "1", "0", "0", "."},
+		{".", "3", ".", "0", "="},
+		{"=", "+", "=", ".", "="},
+	})
+}
+
+func TestNavigationEveryCellWithWrap(t *testing.T) {
+	checkNavigation(t, New(WithWrap()), []navigationCase{
+		{"AC", "0", "7", "/", "+/-"},
+		{"+/-", "0", "8", "AC", "%"},
+		{"%", ".", "9", "+/-", "/"},
+		{"/", "=", "x", "%", "AC"},
+		{"7", "AC", "4", "x", "8"},
+		{"8", "+/-", "5", "7", "9"},
+		{"9", "%", "6", "8", "x"},
+		{"x", "/", "-", "9", "7"},
+		{"4", "7", "1", "-", "5"},
+		{"5", "8", "2", "4", "6"},
+		{"6", "9", "3", "5", "-"},
+		{"-", "x", "+", "6", "4"},
+		{"1", "4", "0", "+", "2"},
+		{"2", "5", "0", "1", "3"},
+		{"3", "6", ".", "2", "+"},
+		{"+", "-", "=", "3", "1"},
+		{"0", "1", "AC", "=", "."},
+		{".", "3", "%", "0", "="},
+		{"=", "+", "/", ".", "0"},
+	})
+}
+
+func TestNavigationRemembersColumnThroughWideKey(t *testing.T) {
+	m := cursorOn(t, New(), "2")
+	m = typeKeys(m, downKey)
+	if cursorLabel(m) != "0" {
+		t.Fatalf("Expected 0 below 2, got %q", cursorLabel(m))
+	}
+	m = typeKeys(m, upKey)
+	if cursorLabel(m) != "2" {
+		t.Errorf("Expected to return to 2 above the wide 0, got %q", cursorLabel(m))
+	}
+
+	// Moving sideways picks up the new column
+	m = typeKeys(m, downKey, rightKey, upKey)
+	if cursorLabel(m) != "3" {
+		t.Errorf("Expected 3 above ., got %q", cursorLabel(m))
+	}
+}
+
+func TestNavigationJumps(t *testing.T) {
+	tests := []struct {
+		from     string
+		key      tea.KeyMsg
+		expected string
+	}{
+		{"9", homeKey, "7"},
+		{"7", endKey, "x"},
+		{"0", endKey, "="},
+		{"=", homeKey, "0"},
+		{"3", pageDownKey, "."},
+		{"3", pageUpKey, "%"},
+		{"0", pageUpKey, "AC"},
+		{"/", pageDownKey, "="},
+	}
+	for _, tt := range tests {
+		got := cursorLabel(typeKeys(cursorOn(t, New(), tt.from), tt.key))
+		if got != tt.expected {
+			t.Errorf("%s from %q: expected %q, got %q", tt.key, tt.from, tt.expected, got)
+		}
+	}
+}
+
+func TestNavigationRaggedRows(t *testing.T) {
+	layout, err := keypad.Parse(`
+[[row]]
+keys = [{ action = "digit-1" }, { action = "digit-2" }, { action = "digit-3" }, { action = "add" }]
+[[row]]
+keys = [{ action = "equals", span = 2 }]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := cursorOn(t, New(WithLayout(layout)), "+")
+	m = typeKeys(m, downKey)
+	if cursorLabel(m) != "=" {
+		t.Errorf("Expected the last key of a shorter row, got %q", cursorLabel(m))
+	}
+	m = typeKeys(m, upKey)
+	if cursorLabel(m) != "+" {
+		t.Errorf("Expected to return to +, got %q", cursorLabel(m))
+	}
+}
@@ -0,0 +1,26 @@
+package calculator
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noticeDuration is how long a notice stays on the LCD.
+const noticeDuration = time.Millisecond * 1500
+
+// showNotice replaces the previous operation line with a short message
+// until noticeDuration has passed.
+func (m model) showNotice(text string) (model, tea.Cmd) {
+	m.notice = text
+	m.noticeTime = time.Now()
+	return m, tick()
+}
+
+// clearExpiredNotice drops the notice once it has been shown long enough.
+func (m model) clearExpiredNotice() model {
+	if m.notice != "" && time.Since(m.noticeTime) > noticeDuration {
+		m.notice = ""
+	}
+	return m
+}
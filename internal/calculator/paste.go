@@ -0,0 +1,68 @@
+package calculator
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+)
+
+// isPaste reports whether msg carries pasted text rather than a key press.
+// Terminals without bracketed paste deliver a paste as a single message
+// with several runes.
+func isPaste(msg tea.KeyMsg) bool {
+	return msg.Paste || (msg.Type == tea.KeyRunes && len(msg.Runes) > 1)
+}
+
+// paste feeds pasted text into the calculator. A number is entered as the
+// current operand; an expression is evaluated and its result entered the
+// same way. Text that does not parse is rejected with a notice and leaves
+// the calculation untouched.
+func (m model) paste(text string) (tea.Model, tea.Cmd) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return m, nil
+	}
+
+	v, err := m.env().Eval(text)
+	var syntaxErr *expr.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return m.showNotice("PASTE: " + syntaxErr.Msg)
+	}
+
+	m.undoStack = pushState(m.undoStack, m.state())
+	m.redoStack = nil
+	m.scroll = 0
+	if err != nil {
+		m.display = "Error"
+		m.isError = true
+		m.operand1 = ""
+		m.operator = ""
+		m.isOperand2 = true
+		return m, nil
+	}
+
+	result := m.numberFormat.Result(v)
+	m = m.enterValue(result)
+	if !expr.IsNumber(text) && m.operator == "" {
+		m.previousDisplay = text + " = " + result
+	}
+	return m, nil
+}
+
+// enterValue puts a complete value on the display as the operand being
+// entered, starting a new calculation after a total like a digit would.
+// A macro being recorded keeps the value itself.
+func (m model) enterValue(value string) model {
+	m = m.record(value)
+	if m.isOperand2 && m.operator == "" {
+		m.previousDisplay = ""
+		m.entries = nil
+	}
+	m.display = value
+	m.isOperand2 = false
+	m.isError = false
+	m.lastButton = ""
+	return m
+}
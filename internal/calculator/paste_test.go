@@ -0,0 +1,94 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pasteMsg(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true}
+}
+
+func TestPasteNumber(t *testing.T) {
+	m := typeKeys(New(), pasteMsg("12.5"))
+	if m.display != "12.5" || m.previousDisplay != "" {
+		t.Errorf("Expected 12.5 entered, got %q / %q", m.previousDisplay, m.display)
+	}
+
+	m = typeKeys(m, runes("x4=")...)
+	if m.display != "50" {
+		t.Errorf("Expected pasted number to be used as an operand, got %s", m.display)
+	}
+}
+
+func TestPasteExpression(t *testing.T) {
+	m := typeKeys(New(), pasteMsg("(12.5 * 4) - 3"))
+	if m.display != "47" {
+		t.Errorf("Expected 47, got %s", m.display)
+	}
+	if m.previousDisplay != "(12.5 * 4) - 3 = 47" {
+		t.Errorf("Unexpected previous display %q", m.previousDisplay)
+	}
+
+	m = typeKeys(m, runes("+3=")...)
+	if m.display != "50" {
+		t.Errorf("Expected result to continue the calculation, got %s", m.display)
+	}
+}
+
+func TestPasteAsSecondOperand(t *testing.T) {
+	m := typeKeys(New(), runes("100-")...)
+	m = typeKeys(m, pasteMsg("2 * 10"))
+	if m.previousDisplay != "100 -" || m.display != "20" {
+		t.Errorf("Expected 20 as second operand of 100 -, got %q / %q", m.previousDisplay, m.display)
+	}
+	m = typeKeys(m, runes("=")...)
+	if m.display != "80" {
+		t.Errorf("Expected 80, got %s", m.display)
+	}
+}
+
+func TestPasteWithoutBracketedPaste(t *testing.T) {
+	m := typeKeys(New(), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("6*7")})
+	if m.display != "42" {
+		t.Errorf("Expected multi-rune input to be treated as a paste, got %s", m.display)
+	}
+}
+
+func TestPasteRejectsInvalidContent(t *testing.T) {
+	m := typeKeys(New(), runes("12+3")...)
+	before := m.state()
+
+	m = typeKeys(m, pasteMsg("hello world"))
+	if m.display != before.display || m.previousDisplay != before.previousDisplay || m.operator != before.operator {
+		t.Errorf("Expected state unchanged after invalid paste, got %+v", m.state())
+	}
+	if !strings.HasPrefix(m.notice, "PASTE: ") {
+		t.Errorf("Expected paste error notice, got %q", m.notice)
+	}
+	if !strings.Contains(m.View(), `name "hello"`) {
+		t.Errorf("Expected the reason on the LCD")
+	}
+}
+
+func TestPasteDivisionByZero(t *testing.T) {
+	m := typeKeys(New(), pasteMsg("1/0"))
+	if m.display != "Error" || !m.isError {
+		t.Errorf("Expected error state, got %+v", m.state())
+	}
+	m = typeKeys(m, runes("c")...)
+	if m.display != "0" {
+		t.Errorf("Expected AC to clear the error")
+	}
+}
+
+func TestPasteCanBeUndone(t *testing.T) {
+	m := typeKeys(New(), runes("5")...)
+	m = typeKeys(m, pasteMsg("99"))
+	m = m.undo()
+	if m.display != "5" {
+		t.Errorf("Expected undo to remove the paste, got %s", m.display)
+	}
+}
@@ -0,0 +1,125 @@
+package calculator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// State is what the LCD shows after a key of a replay, in the number
+// format of the calculator.
+type State struct {
+	Key             string // the key pressed, empty for the final state
+	Display         string
+	PreviousDisplay string
+}
+
+// keyTypes maps the names of special keys, as Bubble Tea writes them, to
+// their key types.
+var keyTypes = func() map[string]tea.KeyType {
+	types := map[string]tea.KeyType{"space": tea.KeySpace}
+	for k := tea.KeyType(-100); k < 128; k++ {
+		if name := k.String(); name != "" && k != tea.KeyRunes {
+			types[name] = k
+		}
+	}
+	return types
+}()
+
+// ParseKeys reads a key script. Each character is a key typed as is, and
+// a name in angle brackets is a special key, such as <enter>, <space>,
+// <delete>, <up> or <ctrl+z>. Whitespace between keys is ignored and #
+// starts a comment that runs to the end of the line.
+//
+// Keys mean what they mean at the terminal, so a key that opens a screen
+// or a prompt, such as s, ?, r, v, @ or m when stopping a recording, takes
+// the keys after it until the screen is closed, usually with <esc>, or the
+// prompt is answered.
+func ParseKeys(script string) ([]tea.KeyMsg, error) {
+	var keys []tea.KeyMsg
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '<':
+			end := slices.Index(runes[i:], '>')
+			if end < 0 {
+				return nil, fmt.Errorf("missing '>' after %q", string(runes[i:]))
+			}
+			k, err := namedKey(string(runes[i+1 : i+end]))
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, k)
+			i += end
+		default:
+			keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	return keys, nil
+}
+
+// namedKey returns the key called name, such as enter or alt+x.
+func namedKey(name string) (tea.KeyMsg, error) {
+	base, alt := strings.CutPrefix(name, "alt+")
+	if t, ok := keyTypes[base]; ok {
+		return tea.KeyMsg{Type: t, Alt: alt}, nil
+	}
+	if r := []rune(base); len(r) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: r, Alt: alt}, nil
+	}
+	return tea.KeyMsg{}, fmt.Errorf("unknown key <%s>", name)
+}
+
+// Replay feeds keys into the calculator without a terminal, as if they
+// were typed, and returns the final state. Keys bound to calculator
+// buttons press them through HandleButtonPress unless a screen or prompt
+// is waiting for input; other keys are handled as at the terminal. step,
+// when not nil, is called with the state after each key. A quit key ends
+// the replay.
+func (m model) Replay(keys []tea.KeyMsg, step func(State)) State {
+	for _, k := range keys {
+		m = m.replayKey(k)
+		if step != nil {
+			step(m.replayState(k.String()))
+		}
+		if m.isQuitting {
+			break
+		}
+	}
+	return m.replayState("")
+}
+
+// replayKey presses the button bound to k, or hands k to Update.
+func (m model) replayKey(k tea.KeyMsg) model {
+	if btn, ok := m.keys.button(k); ok && !m.awaitingKey() {
+		m, _ = m.HandleButtonPress(btn)
+		return m
+	}
+	updated, _ := m.Update(k)
+	return updated.(model)
+}
+
+// awaitingKey reports whether a screen or prompt takes the next key
+// instead of the keypad.
+func (m model) awaitingKey() bool {
+	return m.showHelp || m.showSettings || m.showMemory || m.reviewing ||
+		m.macroPrompt != macroNone || m.memoryPrompt != memoryNone
+}
+
+// replayState returns the LCD lines after key as State.
+func (m model) replayState(key string) State {
+	return State{
+		Key:             key,
+		Display:         m.numberFormat.Line(m.display),
+		PreviousDisplay: m.numberFormat.Line(m.previousDisplay),
+	}
+}
@@ -0,0 +1,99 @@
+package calculator
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		script   string
+		expected []string
+	}{
+		{"1+2=", []string{"1", "+", "2", "="}},
+		{"12 * 3\n=", []string{"1", "2", "*", "3", "="}},
+		{"5<enter><delete><ctrl+z>", []string{"5", "enter", "delete", "ctrl+z"}},
+		{"<space><up><alt+x>", []string{" ", "up", "alt+x"}},
+		{"# a comment\n9 # another\n=", []string{"9", "="}},
+	}
+	for _, tt := range tests {
+		keys, err := ParseKeys(tt.script)
+		if err != nil {
+			t.Errorf("ParseKeys(%q): %v", tt.script, err)
+			continue
+		}
+		got := make([]string, len(keys))
+		for i, k := range keys {
+			got[i] = k.String()
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseKeys(%q): expected %q, got %q", tt.script, tt.expected, got)
+		}
+	}
+}
+
+func TestParseKeysErrors(t *testing.T) {
+	for _, script := range []string{"1<bogus>", "1<enter", "<>"} {
+		if _, err := ParseKeys(script); err == nil {
+			t.Errorf("ParseKeys(%q): expected an error", script)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	keys, err := ParseKeys("12+3=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []State
+	final := New(WithSound(false)).Replay(keys, func(s State) { steps = append(steps, s) })
+
+	expected := State{Display: "15", PreviousDisplay: "12 + 3 = 15"}
+	if final != expected {
+		t.Errorf("expected %+v, got %+v", expected, final)
+	}
+	if len(steps) != len(keys) {
+		t.Fatalf("expected %d steps, got %d", len(keys), len(steps))
+	}
+	if s := steps[2]; s != (State{Key: "+", Display: "12", PreviousDisplay: "12 +"}) {
+		t.Errorf("unexpected state after +: %+v", s)
+	}
+}
+
+func TestReplayKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		script   string
+		expected string
+	}{
+		{"grouped", []Option{WithNumberFormat(numfmt.Format{Decimals: numfmt.Auto, Notation: numfmt.Grouped})}, "1000*1000=", "1,000,000"},
+		{"screen closed", nil, "s<esc>1+2=", "3"},
+		{"screen open", nil, "s1+2=", "0"},
+		{"macro", nil, "m+3<ctrl+z>4=m1 c 10 @1", "14"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseKeys(tt.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			final := New(append(tt.options, WithSound(false))...).Replay(keys, nil)
+			if final.Display != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, final.Display)
+			}
+		})
+	}
+}
+
+func TestReplayStopsOnQuit(t *testing.T) {
+	keys := append(runes("7"), tea.KeyMsg{Type: tea.KeyEsc}, runes("8")[0])
+	var steps int
+	final := New(WithSound(false)).Replay(keys, func(State) { steps++ })
+	if final.Display != "7" || steps != 2 {
+		t.Errorf("expected the replay to stop at esc on 7, got %q after %d steps", final.Display, steps)
+	}
+}
@@ -0,0 +1,152 @@
+package calculator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// entry is one step of the current calculation: the value on the display
+// when op was pressed. A carried entry continues from the previous total,
+// so its value is recomputed rather than entered.
+type entry struct {
+	value   string
+	op      string
+	carried bool
+}
+
+// appendEntry copies entries before appending, like pushState.
+func appendEntry(entries []entry, e entry) []entry {
+	return append(entries[:len(entries):len(entries)], e)
+}
+
+// startReview enters check-and-correct mode on the first entry of the
+// current calculation.
+func (m model) startReview() model {
+	if len(m.entries) == 0 {
+		return m
+	}
+	m.reviewing = true
+	m.reviewIndex = 0
+	m.reviewEdit = ""
+	return m
+}
+
+// updateReview handles keys while stepping through the entries.
+func (m model) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		m.isQuitting = true
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Esc), key.Matches(msg, m.keys.Review):
+		m.reviewing = false
+		return m, nil
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Left):
+		if m.reviewIndex > 0 {
+			m.reviewIndex--
+			m.reviewEdit = ""
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Down), key.Matches(msg, m.keys.Right):
+		if m.reviewIndex < len(m.entries)-1 {
+			m.reviewIndex++
+			m.reviewEdit = ""
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Enter), key.Matches(msg, m.keys.Actions["equals"]):
+		if m.reviewEdit == "" {
+			return m, nil
+		}
+		return m.correct(m.reviewIndex, m.reviewEdit), nil
+	case msg.Type == tea.KeyBackspace:
+		if m.reviewEdit != "" {
+			m.reviewEdit = m.reviewEdit[:len(m.reviewEdit)-1]
+		}
+		return m, nil
+	}
+
+	if m.entries[m.reviewIndex].carried {
+		return m, nil
+	}
+	btn, ok := m.keys.button(msg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case isNumber(btn):
+		if m.reviewEdit == "0" || m.reviewEdit == "-0" {
+			m.reviewEdit = strings.TrimSuffix(m.reviewEdit, "0")
+		}
+		m.reviewEdit += btn
+	case btn == ".":
+		if m.reviewEdit == "" {
+			m.reviewEdit = "0"
+		}
+		if !strings.Contains(m.reviewEdit, ".") {
+			m.reviewEdit += "."
+		}
+	case btn == "+/-":
+		if m.reviewEdit == "" {
+			m.reviewEdit = m.entries[m.reviewIndex].value
+		}
+		if strings.HasPrefix(m.reviewEdit, "-") {
+			m.reviewEdit = strings.TrimPrefix(m.reviewEdit, "-")
+		} else {
+			m.reviewEdit = "-" + m.reviewEdit
+		}
+	}
+	return m, nil
+}
+
+// correct replaces the value of entry i and replays the calculation from
+// the start. The correction can be undone as a single step.
+func (m model) correct(i int, value string) model {
+	entries := append([]entry(nil), m.entries...)
+	entries[i].value = value
+
+	r := m.replay(entries)
+	// Keep an operand that was being typed when review started.
+	if m.operator != "" && !m.isOperand2 {
+		r.display = m.display
+		r.isOperand2 = false
+	}
+
+	r.recorded = m.recorded
+
+	m.undoStack = pushState(m.undoStack, m.state())
+	m.redoStack = nil
+	m.scroll = 0
+	m.restore(r.state())
+	m.reviewing = false
+	m.reviewEdit = ""
+	return m
+}
+
+// replay runs entries through the engine on a fresh model with the
+// number format and angle unit of m.
+func (m model) replay(entries []entry) model {
+	r := New(WithNumberFormat(m.numberFormat), WithAngle(m.angle))
+	for _, e := range entries {
+		if !e.carried {
+			r.display = e.value
+			r.isOperand2 = false
+		}
+		r = r.apply(e.op)
+	}
+	return r
+}
+
+// reviewLines returns the LCD lines shown for the entry under review.
+func (m model) reviewLines() (string, string) {
+	e := m.entries[m.reviewIndex]
+	value := e.value
+	switch {
+	case m.reviewEdit != "":
+		value = m.reviewEdit + "_"
+	case e.carried:
+		value = "ANS"
+	}
+	return fmt.Sprintf("CHECK %d/%d", m.reviewIndex+1, len(m.entries)), value + " " + e.op
+}
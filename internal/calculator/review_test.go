@@ -0,0 +1,165 @@
+package calculator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+func typeKeys(m model, keys ...tea.KeyMsg) model {
+	for _, k := range keys {
+		updatedModel, _ := m.Update(k)
+		m = updatedModel.(model)
+	}
+	return m
+}
+
+func runes(s string) []tea.KeyMsg {
+	var keys []tea.KeyMsg
+	for _, r := range s {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+func TestEntriesRecordCalculation(t *testing.T) {
+	m := typeKeys(New(), runes("12+3=x2=")...)
+
+	expected := []entry{
+		{value: "12", op: "+"},
+		{value: "3", op: "="},
+		{value: "15", op: "x", carried: true},
+		{value: "2", op: "="},
+	}
+	if !reflect.DeepEqual(m.entries, expected) {
+		t.Errorf("Expected entries %+v, got %+v", expected, m.entries)
+	}
+
+	// A new number after a total starts a new calculation
+	m = typeKeys(m, runes("7")...)
+	if len(m.entries) != 0 {
+		t.Errorf("Expected entries to reset, got %+v", m.entries)
+	}
+
+	m = typeKeys(m, runes("+1c")...)
+	if len(m.entries) != 0 {
+		t.Errorf("Expected AC to reset entries, got %+v", m.entries)
+	}
+}
+
+func TestReviewStepsThroughEntries(t *testing.T) {
+	m := typeKeys(New(), runes("12+3=")...)
+	m = typeKeys(m, runes("r")...)
+	if !m.reviewing {
+		t.Fatalf("Expected review mode after pressing r")
+	}
+
+	prev, curr := m.reviewLines()
+	if prev != "CHECK 1/2" || curr != "12 +" {
+		t.Errorf("Expected first step, got %q / %q", prev, curr)
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDown})
+	prev, curr = m.reviewLines()
+	if prev != "CHECK 2/2" || curr != "3 =" {
+		t.Errorf("Expected second step, got %q / %q", prev, curr)
+	}
+
+	output := m.View()
+	if !strings.Contains(output, "CHECK 2/2") {
+		t.Errorf("Expected review step on the LCD")
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.reviewing || m.isQuitting {
+		t.Errorf("Expected esc to leave review without quitting")
+	}
+	if m.display != "15" {
+		t.Errorf("Expected display unchanged, got %s", m.display)
+	}
+}
+
+func TestReviewCorrectsAndReplays(t *testing.T) {
+	m := typeKeys(New(), runes("12+3=x2=")...)
+	if m.display != "30" {
+		t.Fatalf("Expected 30, got %s", m.display)
+	}
+
+	m = typeKeys(m, runes("r")...)
+	m = typeKeys(m, runes("20")...)
+	prev, curr := m.reviewLines()
+	if prev != "CHECK 1/4" || curr != "20_ +" {
+		t.Errorf("Expected edit in progress, got %q / %q", prev, curr)
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.reviewing {
+		t.Errorf("Expected review to end after the correction")
+	}
+	if m.display != "46" {
+		t.Errorf("Expected (20 + 3) x 2 = 46, got %s", m.display)
+	}
+	if m.previousDisplay != "23 x 2 = 46" {
+		t.Errorf("Unexpected previous display %q", m.previousDisplay)
+	}
+	if m.entries[0].value != "20" || m.entries[2].value != "23" {
+		t.Errorf("Expected entries to be recomputed, got %+v", m.entries)
+	}
+
+	// The correction is a single undo step
+	m = m.undo()
+	if m.display != "30" {
+		t.Errorf("Expected undo to restore 30, got %s", m.display)
+	}
+}
+
+func TestReviewKeepsNumberFormat(t *testing.T) {
+	m := New(WithNumberFormat(numfmt.Format{Decimals: 2}))
+	m = typeKeys(m, runes("1/3=")...)
+	if m.display != "0.33" {
+		t.Fatalf("Expected 0.33, got %s", m.display)
+	}
+
+	m = typeKeys(m, runes("r2")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.display != "0.67" || m.previousDisplay != "2 / 3 = 0.67" {
+		t.Errorf("Expected the correction rounded to 2 decimals, got %q / %q", m.display, m.previousDisplay)
+	}
+}
+
+func TestReviewKeepsOperandInProgress(t *testing.T) {
+	m := typeKeys(New(), runes("5+6=x4")...)
+	m = typeKeys(m, runes("r")...)
+	m = typeKeys(m, runes("9")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.display != "4" || m.operand1 != "15" || m.operator != "x" {
+		t.Errorf("Expected 15 x with 4 still being entered, got %+v", m.state())
+	}
+	m = typeKeys(m, runes("=")...)
+	if m.display != "60" {
+		t.Errorf("Expected 60, got %s", m.display)
+	}
+}
+
+func TestReviewCarriedEntryIsNotEditable(t *testing.T) {
+	m := typeKeys(New(), runes("2+3=+1=")...)
+	m = typeKeys(m, runes("r")...)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	m = typeKeys(m, runes("7")...)
+
+	_, curr := m.reviewLines()
+	if curr != "ANS +" {
+		t.Errorf("Expected carried entry to stay unedited, got %q", curr)
+	}
+}
+
+func TestReviewWithoutEntries(t *testing.T) {
+	m := typeKeys(New(), runes("r")...)
+	if m.reviewing {
+		t.Errorf("Expected no review without a calculation")
+	}
+}
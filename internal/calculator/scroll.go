@@ -0,0 +1,51 @@
+package calculator
+
+// Overflow markers shown where an LCD line has more to see.
+const (
+	scrollMarkLeft  = "‹"
+	scrollMarkRight = "›"
+)
+
+// scrollWindow returns the part of s that fits width columns, offset
+// columns back from its end, with a marker on each side that has more.
+// Text that fits is returned unchanged.
+func scrollWindow(s string, width, offset int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width < 2 {
+		return ""
+	}
+	offset = min(max(offset, 0), maxScroll(len(r), width))
+
+	end := len(r) - offset
+	prefix, suffix := "", ""
+	room := width
+	if offset > 0 {
+		suffix = scrollMarkRight
+		room--
+	}
+	start := end - room
+	if start > 0 {
+		prefix = scrollMarkLeft
+		start++
+	}
+	return prefix + string(r[start:end]) + suffix
+}
+
+// maxScroll returns the offset that shows the start of a line of n
+// columns in width columns.
+func maxScroll(n, width int) int {
+	return max(n-(width-1), 0)
+}
+
+// scrollBy moves the LCD view by delta columns towards the start of the
+// lines, stopping where the longest line is shown from its start.
+func (m model) scrollBy(delta int) model {
+	width := m.lcdTextWidth(m.geometry())
+	previous, current := m.lcdLines()
+	limit := max(maxScroll(len([]rune(previous)), width), maxScroll(len([]rune(current)), width))
+	m.scroll = min(max(m.scroll+delta, 0), limit)
+	return m
+}
@@ -0,0 +1,92 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestScrollWindow(t *testing.T) {
+	tests := []struct {
+		offset   int
+		expected string
+	}{
+		{0, "‹67890"},
+		{1, "‹6789›"},
+		{2, "‹5678›"},
+		{5, "12345›"},
+		{50, "12345›"}, // clamped to the start
+		{-3, "‹67890"},
+	}
+	for _, tt := range tests {
+		if got := scrollWindow("1234567890", 6, tt.offset); got != tt.expected {
+			t.Errorf("Offset %d: expected %q, got %q", tt.offset, tt.expected, got)
+		}
+	}
+	if got := scrollWindow("12345", 6, 3); got != "12345" {
+		t.Errorf("Expected text that fits to be unchanged, got %q", got)
+	}
+}
+
+func TestScrollLongValue(t *testing.T) {
+	m := typeKeys(New(), runes("12345678901234567890123456789")...)
+	if m.display != "12345678901234567890123456789" {
+		t.Fatalf("Expected the full value to be kept, got %s", m.display)
+	}
+	if !strings.Contains(m.View(), "‹") {
+		t.Errorf("Expected a left overflow marker:\n%s", m.View())
+	}
+
+	width := m.lcdTextWidth(m.geometry())
+	for i := 0; i < 40; i++ {
+		m = typeKeys(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	}
+	if m.scroll != maxScroll(len(m.display), width) {
+		t.Errorf("Expected scrolling to stop at the start, got offset %d", m.scroll)
+	}
+	if !strings.Contains(m.View(), "123456") || !strings.Contains(m.View(), "›") {
+		t.Errorf("Expected the start and a right overflow marker:\n%s", m.View())
+	}
+
+	m = typeKeys(m, runes("]]")...)
+	if m.scroll != maxScroll(len(m.display), width)-2 {
+		t.Errorf("Expected ] to scroll back right, got offset %d", m.scroll)
+	}
+
+	// A new key press shows the end again
+	m = typeKeys(m, runes("+")...)
+	if m.scroll != 0 {
+		t.Errorf("Expected scroll to reset, got %d", m.scroll)
+	}
+	if m.display != "12345678901234567890123456789" {
+		t.Errorf("Expected the value untouched, got %s", m.display)
+	}
+}
+
+func TestScrollResetsOnUndoRedoAndCorrection(t *testing.T) {
+	m := typeKeys(New(WithSound(false)), runes("12345678901234567890123456789+1")...)
+	scrolled := func(m model) model {
+		return typeKeys(m, tea.KeyMsg{Type: tea.KeyShiftLeft}, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	}
+	if scrolled(m).scroll == 0 {
+		t.Fatal("Expected the long value to scroll")
+	}
+	if m = typeKeys(scrolled(m), tea.KeyMsg{Type: tea.KeyCtrlZ}); m.scroll != 0 {
+		t.Errorf("Expected undo to show the end again, got offset %d", m.scroll)
+	}
+	if m = typeKeys(scrolled(m), tea.KeyMsg{Type: tea.KeyCtrlY}); m.scroll != 0 {
+		t.Errorf("Expected redo to show the end again, got offset %d", m.scroll)
+	}
+	m = typeKeys(m, runes("=")...)
+	if m = scrolled(m).correct(1, "2"); m.scroll != 0 {
+		t.Errorf("Expected a review correction to show the end again, got offset %d", m.scroll)
+	}
+}
+
+func TestScrollWithoutOverflow(t *testing.T) {
+	m := typeKeys(New(), runes("12[")...)
+	if m.scroll != 0 {
+		t.Errorf("Expected nothing to scroll, got %d", m.scroll)
+	}
+}
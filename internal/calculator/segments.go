@@ -0,0 +1,100 @@
+package calculator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+// segmentGlyphs draw characters of the display as seven-segment digits,
+// three lines high. Digits are three columns wide, the decimal point and
+// thousands separator one. The exponent of scientific values is shown
+// with the E glyph.
+var segmentGlyphs = map[rune][3]string{
+	'0': {" _ ", "| |", "|_|"},
+	'1': {"   ", "  |", "  |"},
+	'2': {" _ ", " _|", "|_ "},
+	'3': {" _ ", " _|", " _|"},
+	'4': {"   ", "|_|", "  |"},
+	'5': {" _ ", "|_ ", " _|"},
+	'6': {" _ ", "|_ ", "|_|"},
+	'7': {" _ ", "  |", "  |"},
+	'8': {" _ ", "|_|", "|_|"},
+	'9': {" _ ", "|_|", " _|"},
+	'-': {"   ", " _ ", "   "},
+	'.': {" ", " ", "."},
+	',': {" ", " ", ","},
+	'E': {" _ ", "|_ ", "|_ "},
+	'e': {" _ ", "|_ ", "|_ "},
+	'r': {"   ", " _ ", "|  "},
+	'o': {"   ", " _ ", "|_|"},
+	' ': {"   ", "   ", "   "},
+}
+
+// segmentWidth returns the columns value takes as seven-segment glyphs, or
+// false when it has characters without a glyph.
+func segmentWidth(value string) (int, bool) {
+	width := 0
+	for _, r := range value {
+		glyph, ok := segmentGlyphs[r]
+		if !ok {
+			return 0, false
+		}
+		width += len(glyph[0])
+	}
+	return width, true
+}
+
+// fitSegments shortens value, as f shows it, to fit width columns of
+// seven-segment glyphs by rounding away decimal places with the rounding
+// of f, like a hardware LCD with fewer digits. The exponent of a
+// scientific value is kept whole, without its + sign, which has no glyph.
+// It returns false when even the whole number part does not fit.
+func fitSegments(value string, width int, f numfmt.Format) (string, bool) {
+	v, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	mantissa, _, scientific := strings.Cut(value, "e")
+	_, frac, _ := strings.Cut(mantissa, ".")
+	for decimals := len(frac); ; decimals-- {
+		fitted := strings.Replace(value, "e+", "e", 1)
+		w, ok := segmentWidth(fitted)
+		if !ok {
+			return "", false
+		}
+		if w <= width {
+			return fitted, true
+		}
+		if decimals == 0 || err != nil {
+			return "", false
+		}
+		shorter := numfmt.Format{Decimals: decimals - 1, Rounding: f.Rounding}
+		if scientific {
+			shorter.Notation = numfmt.Scientific
+		}
+		value = f.Display(shorter.Result(v))
+	}
+}
+
+// renderSegments returns value as three lines of seven-segment glyphs.
+func renderSegments(value string) []string {
+	var lines [3]strings.Builder
+	for _, r := range value {
+		for i, part := range segmentGlyphs[r] {
+			lines[i].WriteString(part)
+		}
+	}
+	return []string{lines[0].String(), lines[1].String(), lines[2].String()}
+}
+
+// renderDisplayValue renders the current value on the LCD: as
+// seven-segment digits in segment mode when they fit width, otherwise as
+// text, scrolled when it is wider than the LCD.
+func (m model) renderDisplayValue(value string, width int) string {
+	style := m.styles.display.Width(width)
+	if m.segments {
+		if fitted, ok := fitSegments(value, width, m.numberFormat); ok {
+			return style.Render(strings.Join(renderSegments(fitted), "\n"))
+		}
+	}
+	return style.Render(scrollWindow(value, width, m.scroll))
+}
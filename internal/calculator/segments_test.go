@@ -0,0 +1,91 @@
+package calculator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+func TestRenderSegments(t *testing.T) {
+	got := strings.Join(renderSegments("-12.5"), "\n")
+	expected := strings.Join([]string{
+		"   " + "   " + " _ " + " " + " _ ",
+		" _ " + "  |" + " _|" + " " + "|_ ",
+		"   " + "  |" + "|_ " + "." + " _|",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Unexpected glyphs:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestFitSegments(t *testing.T) {
+	grouped := numfmt.Format{Decimals: numfmt.Auto, Notation: numfmt.Grouped}
+	tests := []struct {
+		value    string
+		width    int
+		format   numfmt.Format
+		expected string
+		ok       bool
+	}{
+		{"123", 9, numfmt.Default(), "123", true},
+		{"3.14159", 13, numfmt.Default(), "3.142", true},
+		{"3.149", 10, numfmt.Default(), "3.15", true},
+		{"3.149", 10, numfmt.Format{Rounding: numfmt.Down}, "3.14", true},
+		{"9.99", 4, numfmt.Default(), "", false},
+		{"9.96", 7, numfmt.Default(), "10", true},
+		{"1,234.56", 13, grouped, "1,235", true},
+		{"3.14159", 4, numfmt.Default(), "3", true},
+		{"12345", 12, numfmt.Default(), "", false},
+		{"Error", 15, numfmt.Default(), "Error", true},
+		{"1e+21", 30, numfmt.Default(), "1e21", true},
+		{"-1.2345675e+06", 22, numfmt.Default(), "-1.23e06", true},
+		{"9.99e+06", 12, numfmt.Default(), "1e07", true},
+		{"1.5e-07", 21, numfmt.Default(), "1.5e-07", true},
+		{"1e+21", 11, numfmt.Default(), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := fitSegments(tt.value, tt.width, tt.format)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("fitSegments(%q, %d, %+v) = %q, %v; expected %q, %v", tt.value, tt.width, tt.format, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestSegmentScientific(t *testing.T) {
+	m := New(WithSegments(), WithNumberFormat(numfmt.Format{Decimals: 2, Notation: numfmt.Scientific}))
+	m = typeKeys(m, runes("1234567x1=")...)
+	if m.display != "1.23e+06" {
+		t.Fatalf("Expected 1.23e+06, got %s", m.display)
+	}
+	output := m.View()
+	for _, line := range renderSegments("1.23e06") {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected %q of 1.23E06 in seven-segment digits:\n%s", line, output)
+		}
+	}
+}
+
+func TestSegmentMode(t *testing.T) {
+	m := typeKeys(New(), runes("42L")...)
+	if !m.segments {
+		t.Fatalf("Expected L to turn on segment mode")
+	}
+	output := m.View()
+	for _, line := range renderSegments("42") {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected %q of 42 in seven-segment digits:\n%s", line, output)
+		}
+	}
+
+	m = typeKeys(m, runes("/0=")...)
+	if !strings.Contains(m.View(), renderSegments("Error")[2]) {
+		t.Errorf("Expected Error in segments:\n%s", m.View())
+	}
+
+	m = typeKeys(New(WithSegments()), runes("7")...)
+	m = resize(m, 80, 40)
+	if g := m.geometry(); g.tooSmall {
+		t.Errorf("Expected the taller LCD to fit 80x40")
+	}
+}
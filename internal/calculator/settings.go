@@ -0,0 +1,261 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+// savedMsg reports the result of saving a file, with the notice to show
+// when it was saved.
+type savedMsg struct {
+	notice string
+	err    error
+}
+
+// WithConfigFile lets the settings screen save to the config file at
+// path. Settings changed in the app are written on top of c, so settings
+// the screen does not show are kept as they were in the file.
+func WithConfigFile(path string, c config.Config) Option {
+	return func(m *model) {
+		m.configPath = path
+		m.config = c
+	}
+}
+
+// setting is a line of the settings screen.
+type setting struct {
+	name  string
+	value func(m model) string
+	// change moves the setting delta steps through its choices and
+	// applies it at once.
+	change func(m model, delta int) model
+}
+
+// step moves i delta steps through n choices, wrapping at either end.
+func step(i, delta, n int) int {
+	return ((i+delta)%n + n) % n
+}
+
+var settings = []setting{
+	{
+		name: "Sound",
+		value: func(m model) string {
+			if m.sound {
+				return "on"
+			}
+			return "off"
+		},
+		change: func(m model, delta int) model {
+			m.sound = !m.sound
+			return m
+		},
+	},
+	{
+		name:  "Theme",
+		value: func(m model) string { return m.theme.Name },
+		change: func(m model, delta int) model {
+			m.theme = m.themes[step(max(m.themeIndex(), 0), delta, len(m.themes))]
+			m.styles = newStyles(m.theme, m.colorProfile)
+			return m
+		},
+	},
+	{
+		name: "Decimals",
+		value: func(m model) string {
+			if m.numberFormat.Decimals == numfmt.Auto {
+				return "auto"
+			}
+			return strconv.Itoa(m.numberFormat.Decimals)
+		},
+		change: func(m model, delta int) model {
+			// Auto comes before 0 in the choices
+			choices := numfmt.MaxDecimals + 2
+			m.numberFormat.Decimals = step(m.numberFormat.Decimals+1, delta, choices) - 1
+			return m
+		},
+	},
+	{
+		name:  "Rounding",
+		value: func(m model) string { return m.numberFormat.Rounding.String() },
+		change: func(m model, delta int) model {
+			roundings := numfmt.Roundings()
+			m.numberFormat.Rounding = roundings[step(int(m.numberFormat.Rounding), delta, len(roundings))]
+			return m
+		},
+	},
+	{
+		name:  "Angle unit",
+		value: func(m model) string { return m.angle.String() },
+		change: func(m model, delta int) model {
+			angles := expr.Angles()
+			m.angle = angles[step(int(m.angle), delta, len(angles))]
+			return m
+		},
+	},
+	{
+		name:  "Number format",
+		value: func(m model) string { return m.numberFormat.Notation.String() },
+		change: func(m model, delta int) model {
+			notations := numfmt.Notations()
+			m.numberFormat.Notation = notations[step(int(m.numberFormat.Notation), delta, len(notations))]
+			return m
+		},
+	},
+}
+
+// settingsKeys are the keys of the settings screen, fixed so the screen
+// can always be left the same way.
+var settingsKeys = struct {
+	Select, Change, Save, Close key.Binding
+	Previous, Next              key.Binding
+}{
+	Select:   key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "select")),
+	Change:   key.NewBinding(key.WithKeys("left", "right"), key.WithHelp("←/→", "change")),
+	Save:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "save")),
+	Close:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close")),
+	Previous: key.NewBinding(key.WithKeys("left", "h")),
+	Next:     key.NewBinding(key.WithKeys("right", "l", " ")),
+}
+
+// settingItem is a setting as an item of the settings list.
+type settingItem struct {
+	name, value string
+}
+
+func (i settingItem) FilterValue() string { return i.name }
+
+// Widths of the settings list and its column of setting names.
+const (
+	settingsWidth     = 36
+	settingsNameWidth = 14
+)
+
+// settingsDelegate draws a setting on one line, the selected one in the
+// highlight style and marked so it shows without color.
+type settingsDelegate struct {
+	styles styles
+}
+
+func (d settingsDelegate) Height() int                         { return 1 }
+func (d settingsDelegate) Spacing() int                        { return 0 }
+func (d settingsDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+func (d settingsDelegate) Render(w io.Writer, l list.Model, index int, item list.Item) {
+	i := item.(settingItem)
+	name := fmt.Sprintf("%-*s", settingsNameWidth, i.name)
+	if index != l.Index() {
+		fmt.Fprint(w, d.styles.helpDesc.Render("  "+name+"  "+i.value+"  "))
+		return
+	}
+	fmt.Fprint(w, d.styles.highlight.Align(lipgloss.Left).Render("› "+name+"‹ "+i.value+" ›"))
+}
+
+// settingItems returns the settings with their current values.
+func (m model) settingItems() []list.Item {
+	items := make([]list.Item, len(settings))
+	for i, s := range settings {
+		items[i] = settingItem{name: s.name, value: s.value(m)}
+	}
+	return items
+}
+
+// openSettings shows the settings screen with the first setting selected.
+func (m model) openSettings() model {
+	l := list.New(m.settingItems(), settingsDelegate{styles: m.styles}, settingsWidth, len(settings))
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowPagination(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.DisableQuitKeybindings()
+	l.SetSize(settingsWidth, len(settings))
+	m.settingsList = l
+	m.showSettings = true
+	return m
+}
+
+// updateSettings handles keys on the settings screen. Changes apply as
+// they are made; enter also saves them to the config file, and esc or the
+// settings key closes the screen keeping them for this session.
+func (m model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, settingsKeys.Close), key.Matches(msg, m.keys.Settings):
+		m.showSettings = false
+		return m, nil
+	case key.Matches(msg, m.keys.Quit):
+		m.isQuitting = true
+		return m, tea.Quit
+	case key.Matches(msg, settingsKeys.Save):
+		m.showSettings = false
+		return m, m.saveCmd()
+	case key.Matches(msg, settingsKeys.Previous):
+		return m.changeSetting(-1), nil
+	case key.Matches(msg, settingsKeys.Next):
+		return m.changeSetting(1), nil
+	}
+	var cmd tea.Cmd
+	m.settingsList, cmd = m.settingsList.Update(msg)
+	return m, cmd
+}
+
+// changeSetting changes the selected setting and shows its new value.
+func (m model) changeSetting(delta int) model {
+	m = settings[m.settingsList.Index()].change(m, delta)
+	m.settingsList.SetDelegate(settingsDelegate{styles: m.styles})
+	m.settingsList.SetItems(m.settingItems())
+	return m
+}
+
+// savedConfig returns the config file with the settings of the app. A
+// theme loaded from a file keeps the theme setting of the file, which
+// names the file rather than the theme.
+func (m model) savedConfig() config.Config {
+	c := m.config
+	c.Sound = m.sound
+	if m.theme.Path == "" {
+		c.Theme = m.theme.Name
+	}
+	c.Precision = m.numberFormat.Decimals
+	c.Rounding = m.numberFormat.Rounding.String()
+	c.NumberFormat = m.numberFormat.Notation.String()
+	c.Angle = m.angle.String()
+	return c
+}
+
+// saveCmd returns a command that writes the settings to the config file.
+func (m model) saveCmd() tea.Cmd {
+	path, c := m.configPath, m.savedConfig()
+	return func() tea.Msg {
+		if path == "" {
+			return savedMsg{err: errors.New("no config file")}
+		}
+		return savedMsg{notice: "SAVED", err: config.Save(path, c)}
+	}
+}
+
+// renderSettingsOverlay shows the settings screen in place of the
+// calculator.
+func (m model) renderSettingsOverlay() string {
+	items := m.settingsList.View()
+	keys := m.helpModel(0).ShortHelpView([]key.Binding{
+		settingsKeys.Select, settingsKeys.Change, settingsKeys.Save, settingsKeys.Close,
+	})
+	w := max(lipgloss.Width(items), lipgloss.Width(keys))
+	title := m.styles.logo.Width(w).Render("SETTINGS")
+	footer := m.styles.help.Width(w).Render(keys)
+	box := m.styles.body.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", items, "", footer))
+	if m.width == 0 || m.height == 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
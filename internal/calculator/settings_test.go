@@ -0,0 +1,123 @@
+package calculator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+// selectSetting opens the settings screen on the setting called name.
+func selectSetting(t *testing.T, m model, name string) model {
+	t.Helper()
+	m = typeKeys(m, runes("s")...)
+	for _, s := range settings {
+		if s.name == name {
+			return m
+		}
+		m = typeKeys(m, downKey)
+	}
+	t.Fatalf("No setting %q", name)
+	return m
+}
+
+func TestSettingsOpenAndClose(t *testing.T) {
+	m := typeKeys(New(), runes("s")...)
+	if !m.showSettings {
+		t.Fatal("Expected s to open the settings")
+	}
+	view := m.View()
+	for _, s := range settings {
+		if !strings.Contains(view, s.name) {
+			t.Errorf("Expected %q in the settings screen", s.name)
+		}
+	}
+	if !strings.Contains(view, "› Sound") {
+		t.Error("Expected the first setting to be selected")
+	}
+
+	if m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc}); m.showSettings || m.isQuitting {
+		t.Error("Expected esc to close the settings without quitting")
+	}
+	if m = typeKeys(m, runes("ss")...); m.showSettings {
+		t.Error("Expected s to close the settings")
+	}
+}
+
+func TestSettingsApplyLive(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  []tea.KeyMsg
+		check func(m model) bool
+	}{
+		{"Sound", []tea.KeyMsg{rightKey}, func(m model) bool { return !m.sound }},
+		{"Theme", []tea.KeyMsg{rightKey}, func(m model) bool { return m.theme.Name == "dark" }},
+		{"Theme", []tea.KeyMsg{leftKey}, func(m model) bool { return m.theme.Name == "solarized" }},
+		{"Decimals", []tea.KeyMsg{rightKey, rightKey, rightKey}, func(m model) bool { return m.numberFormat.Decimals == 2 }},
+		{"Decimals", []tea.KeyMsg{leftKey}, func(m model) bool { return m.numberFormat.Decimals == numfmt.MaxDecimals }},
+		{"Rounding", []tea.KeyMsg{rightKey}, func(m model) bool { return m.numberFormat.Rounding == numfmt.HalfEven }},
+		{"Angle unit", []tea.KeyMsg{rightKey}, func(m model) bool { return m.angle == expr.Radians }},
+		{"Number format", []tea.KeyMsg{rightKey}, func(m model) bool { return m.numberFormat.Notation == numfmt.Grouped }},
+	}
+	for _, tt := range tests {
+		m := typeKeys(selectSetting(t, New(), tt.name), tt.keys...)
+		if !tt.check(m) {
+			t.Errorf("%s: setting not applied, got\n%s", tt.name, m.View())
+		}
+	}
+}
+
+func TestSettingsShowNewValue(t *testing.T) {
+	m := typeKeys(selectSetting(t, New(), "Number format"), rightKey)
+	if !strings.Contains(m.View(), "‹ grouped ›") {
+		t.Errorf("Expected the new value on the settings screen, got\n%s", m.View())
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = typeKeys(m, runes("1234")...)
+	if _, current := m.lcdLines(); current != "1,234" {
+		t.Errorf("Expected the calculator to use the new format, got %q", current)
+	}
+}
+
+func TestSettingsSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	file := config.Default()
+	file.Keys = map[string][]string{"quit": {"Q"}}
+
+	m := New(WithConfigFile(path, file))
+	m = typeKeys(selectSetting(t, m, "Decimals"), rightKey, rightKey, rightKey)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.showSettings || cmd == nil {
+		t.Fatal("Expected enter to close the settings and save")
+	}
+	updated, _ = m.Update(cmd())
+	if m = updated.(model); m.notice != "SAVED" {
+		t.Errorf("Expected a SAVED notice, got %q", m.notice)
+	}
+
+	saved, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if saved.Precision != 2 {
+		t.Errorf("Expected precision 2 in the saved file, got %d", saved.Precision)
+	}
+	if saved.Keys["quit"][0] != "Q" {
+		t.Errorf("Expected settings not on the screen to be kept, got %v", saved.Keys)
+	}
+}
+
+func TestSettingsSaveWithoutConfigFile(t *testing.T) {
+	m := typeKeys(New(), runes("s")...)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ := m.Update(cmd())
+	if notice := updated.(model).notice; notice != "SAVE FAILED" {
+		t.Errorf("Expected a SAVE FAILED notice, got %q", notice)
+	}
+}
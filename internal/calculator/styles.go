@@ -0,0 +1,162 @@
+package calculator
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
+	"github.com/dmisiuk/goose-tui-calculator/internal/theme"
+	"github.com/muesli/termenv"
+)
+
+// styles are the lipgloss styles built from a theme.
+type styles struct {
+	logo             lipgloss.Style
+	displayContainer lipgloss.Style
+	display          lipgloss.Style
+	previousDisplay  lipgloss.Style
+	annunciators     lipgloss.Style
+
+	// Keypad classes drawn with each style
+	classes map[keypad.Class]lipgloss.Style
+
+	// Visual feedback
+	highlight      lipgloss.Style
+	pressed        lipgloss.Style
+	directKeyboard lipgloss.Style
+
+	// Marks around the label of a key in each feedback state, so the state
+	// does not rely on color alone in monochrome terminals
+	highlightMarks      marks
+	pressedMarks        marks
+	directKeyboardMarks marks
+
+	body     lipgloss.Style
+	help     lipgloss.Style
+	helpKey  lipgloss.Style
+	helpDesc lipgloss.Style
+	tapePane lipgloss.Style
+}
+
+// marks surround a key label.
+type marks struct {
+	open, close string
+}
+
+// wrap returns label inside the marks, or label alone when the marked
+// label would not fit in width columns.
+func (k marks) wrap(label string, width int) string {
+	marked := k.open + label + k.close
+	if lipgloss.Width(marked) > width {
+		return label
+	}
+	return marked
+}
+
+// newStyles builds the styles for theme t as shown by color profile p.
+func newStyles(t theme.Theme, p termenv.Profile) styles {
+	if p == termenv.Ascii {
+		return monochromeStyles()
+	}
+	t = t.Degrade(p)
+	c := func(s string) lipgloss.Color { return lipgloss.Color(s) }
+
+	// Buttons - sized by the layout geometry when rendered
+	button := func(bg, fg string) lipgloss.Style {
+		return lipgloss.NewStyle().
+			Bold(true).
+			Background(c(bg)).
+			Foreground(c(theme.Readable(fg, bg))).
+			Align(lipgloss.Center)
+	}
+
+	return styles{
+		logo: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(c(t.Logo)).
+			Align(lipgloss.Center),
+
+		displayContainer: lipgloss.NewStyle().
+			Background(c(t.LCD.Background)).
+			Padding(1, 2),
+		display: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(c(theme.Readable(t.LCD.Text, t.LCD.Background))).
+			Align(lipgloss.Right),
+		previousDisplay: lipgloss.NewStyle().
+			Foreground(c(theme.Readable(t.LCD.Dim, t.LCD.Background))).
+			Align(lipgloss.Right),
+		annunciators: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(c(theme.Readable(t.LCD.Text, t.LCD.Background))),
+
+		classes: map[keypad.Class]lipgloss.Style{
+			keypad.ClassNumber:   button(t.Buttons.Number, t.Buttons.Text),
+			keypad.ClassZero:     button(t.Buttons.Zero, t.Buttons.Text),
+			keypad.ClassOperator: button(t.Buttons.Operator, t.Buttons.Text),
+			keypad.ClassFunction: button(t.Buttons.Function, t.Buttons.Text),
+			keypad.ClassClear:    button(t.Buttons.Clear, t.Buttons.Text),
+			keypad.ClassEquals:   button(t.Buttons.Equals, t.Buttons.Text),
+		},
+
+		highlight:      button(t.Feedback.Highlight, t.Feedback.HighlightText),
+		pressed:        button(t.Feedback.Pressed, t.Feedback.PressedText),
+		directKeyboard: button(t.Feedback.Keyboard, t.Feedback.KeyboardText),
+
+		// Calculator body - NO background, use terminal default
+		body: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(c(t.Border)).
+			Padding(1, 2),
+		help: lipgloss.NewStyle().
+			Foreground(c(t.Help)).
+			Align(lipgloss.Center),
+		helpKey: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(c(t.Logo)),
+		helpDesc: lipgloss.NewStyle().
+			Foreground(c(t.Help)),
+		tapePane: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(c(t.Border)).
+			Padding(0, 1),
+	}
+}
+
+// monochromeStyles draws without color. Keys in a feedback state are set
+// apart by reverse video, underline and marks around their label.
+func monochromeStyles() styles {
+	button := lipgloss.NewStyle().
+		Bold(true).
+		Align(lipgloss.Center)
+	border := lipgloss.NewStyle().Border(lipgloss.RoundedBorder())
+
+	return styles{
+		logo:             lipgloss.NewStyle().Bold(true).Align(lipgloss.Center),
+		displayContainer: lipgloss.NewStyle().Padding(1, 2),
+		display:          lipgloss.NewStyle().Bold(true).Align(lipgloss.Right),
+		previousDisplay:  lipgloss.NewStyle().Align(lipgloss.Right),
+		annunciators:     lipgloss.NewStyle().Bold(true),
+
+		classes: map[keypad.Class]lipgloss.Style{
+			keypad.ClassNumber:   button,
+			keypad.ClassZero:     button,
+			keypad.ClassOperator: button,
+			keypad.ClassFunction: button,
+			keypad.ClassClear:    button,
+			keypad.ClassEquals:   button,
+		},
+
+		highlight:      button.Reverse(true),
+		pressed:        button.Reverse(true).Underline(true),
+		directKeyboard: button.Underline(true),
+
+		highlightMarks:      marks{"[", "]"},
+		pressedMarks:        marks{">", "<"},
+		directKeyboardMarks: marks{">", "<"},
+
+		body:     border.Padding(1, 2),
+		help:     lipgloss.NewStyle().Align(lipgloss.Center),
+		helpKey:  lipgloss.NewStyle().Bold(true),
+		helpDesc: lipgloss.NewStyle(),
+		tapePane: border.Padding(0, 1),
+	}
+}
@@ -0,0 +1,95 @@
+package calculator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tapeValueWidth is the width of the right-aligned value column on the
+// printed tape. Symbols are printed in a column after it.
+const tapeValueWidth = 16
+
+// tapePaneWidth is the width of the tape pane including its border.
+const tapePaneWidth = tapeValueWidth + 6
+
+// Symbols printed in the operator column next to non-operator entries.
+const (
+	tapeTotal    = "*" // result of =
+	tapeSubtotal = "◇" // a total carried into the next calculation
+	tapeError    = "E" // calculation ended in an error
+	tapeClear    = "C" // AC pressed
+)
+
+// tape is the paper roll of print mode. It keeps every printed line for the
+// side pane and appends them to an optional writer such as a tape file.
+type tape struct {
+	w     io.Writer
+	lines []string
+}
+
+func newTape(w io.Writer) *tape {
+	return &tape{w: w}
+}
+
+// print appends a line with value right-aligned in the value column.
+func (t *tape) print(value, symbol string) {
+	pad := strings.Repeat(" ", max(tapeValueWidth-lipgloss.Width(value), 0))
+	t.write(strings.TrimRight(pad+value+" "+symbol, " "))
+}
+
+// feed advances the paper by a blank line, separating calculations.
+func (t *tape) feed() {
+	if len(t.lines) == 0 || t.lines[len(t.lines)-1] == "" {
+		return
+	}
+	t.write("")
+}
+
+func (t *tape) write(line string) {
+	t.lines = append(t.lines, line)
+	if t.w != nil {
+		fmt.Fprintln(t.w, line)
+	}
+}
+
+// tail returns at most the last n printed lines.
+func (t *tape) tail(n int) []string {
+	if len(t.lines) <= n {
+		return t.lines
+	}
+	return t.lines[len(t.lines)-n:]
+}
+
+// print writes an entry to the tape when print mode is on.
+func (m model) print(value, symbol string) {
+	if m.printing {
+		m.tape.print(value, symbol)
+	}
+}
+
+// feed separates calculations on the tape when print mode is on.
+func (m model) feed() {
+	if m.printing {
+		m.tape.feed()
+	}
+}
+
+// renderTapePane renders the end of the tape to fit next to a calculator
+// body of the given height.
+func (m model) renderTapePane(height int) string {
+	// Border takes two lines and the title one more.
+	lines := m.tape.tail(max(height-3, 0))
+	title := "TAPE"
+	if !m.printing {
+		title = "TAPE (off)"
+	}
+	content := title + "\n" + strings.Join(lines, "\n")
+	style := m.styles.tapePane
+	return style.
+		Width(tapePaneWidth - style.GetHorizontalBorderSize()).
+		Height(height - 2).
+		Render(content)
+}
@@ -0,0 +1,104 @@
+package calculator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTapePrintsCalculation(t *testing.T) {
+	var buf bytes.Buffer
+	m := New(WithTape(&buf))
+
+	for _, btn := range []string{"1", "2", "+", "3", "=", "+", "5", "=", "AC"} {
+		updatedModel, _ := m.handleButtonPress(btn)
+		m = updatedModel.(model)
+	}
+
+	expected := strings.Join([]string{
+		"              12 +",
+		"               3 =",
+		"              15 *",
+		"",
+		"               ◇ +",
+		"               5 =",
+		"              20 *",
+		"",
+		"                 C",
+		"",
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected tape:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestTapeValueColumnAlignment(t *testing.T) {
+	tp := newTape(nil)
+	tp.print("1", "+")
+	tp.print("123456.5", "=")
+	tp.print("Error", tapeError)
+
+	for _, line := range tp.lines {
+		if got := strings.LastIndex(line, " "); got != tapeValueWidth {
+			t.Errorf("Symbol in %q starts at column %d, expected %d", line, got+1, tapeValueWidth+1)
+		}
+	}
+}
+
+func TestTapePrintsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	m := New(WithTape(&buf))
+
+	for _, btn := range []string{"5", "/", "0", "="} {
+		updatedModel, _ := m.handleButtonPress(btn)
+		m = updatedModel.(model)
+	}
+
+	if !strings.Contains(buf.String(), "Error E") {
+		t.Errorf("Expected error line on tape, got:\n%s", buf.String())
+	}
+}
+
+func TestPrintModeOffDoesNotPrint(t *testing.T) {
+	m := New()
+
+	for _, btn := range []string{"1", "+", "2", "="} {
+		updatedModel, _ := m.handleButtonPress(btn)
+		m = updatedModel.(model)
+	}
+
+	if len(m.tape.lines) != 0 {
+		t.Errorf("Expected empty tape with print mode off, got %q", m.tape.lines)
+	}
+}
+
+func TestPrintModeToggle(t *testing.T) {
+	m := New()
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = updatedModel.(model)
+	if !m.printing {
+		t.Fatalf("Expected print mode on after pressing p")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = updatedModel.(model)
+	if !m.showTape {
+		t.Fatalf("Expected tape pane after pressing P")
+	}
+
+	for _, r := range "7+1=" {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updatedModel.(model)
+	}
+
+	output := m.View()
+	if !strings.Contains(output, "TAPE") {
+		t.Errorf("Expected tape pane in output")
+	}
+	if !strings.Contains(output, "8 *") {
+		t.Errorf("Expected printed total in tape pane")
+	}
+}
@@ -0,0 +1,48 @@
+package calculator
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dmisiuk/goose-tui-calculator/internal/theme"
+	"github.com/muesli/termenv"
+)
+
+// WithTheme draws the calculator with t. A theme that is not built in
+// joins the themes cycled through in the app.
+func WithTheme(t theme.Theme) Option {
+	return func(m *model) {
+		m.theme = t
+		if m.themeIndex() < 0 {
+			m.themes = append(m.themes[:len(m.themes):len(m.themes)], t)
+		}
+	}
+}
+
+// themeIndex returns the position of the current theme in the cycle, or
+// -1. A theme file is told apart from a built-in theme by its path, so
+// it keeps its place even when it reuses a built-in name.
+func (m model) themeIndex() int {
+	for i, t := range m.themes {
+		if t.Name == m.theme.Name && t.Path == m.theme.Path {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextTheme switches to the next theme and names it on the LCD.
+func (m model) nextTheme() (model, tea.Cmd) {
+	m.theme = m.themes[(m.themeIndex()+1)%len(m.themes)]
+	m.styles = newStyles(m.theme, m.colorProfile)
+	return m.showNotice("THEME " + strings.ToUpper(m.theme.Name))
+}
+
+// WithColorProfile draws the theme with the colors profile p can show.
+// The Ascii profile, used for monochrome terminals and NO_COLOR, draws
+// without color.
+func WithColorProfile(p termenv.Profile) Option {
+	return func(m *model) {
+		m.colorProfile = p
+	}
+}
@@ -0,0 +1,126 @@
+package calculator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/config"
+	"github.com/dmisiuk/goose-tui-calculator/internal/theme"
+	"github.com/muesli/termenv"
+)
+
+func TestThemeCycling(t *testing.T) {
+	m := New()
+	if m.theme.Name != "classic" {
+		t.Fatalf("Expected classic theme by default, got %s", m.theme.Name)
+	}
+
+	var names []string
+	for range theme.Builtins() {
+		m = typeKeys(m, runes("t")...)
+		names = append(names, m.theme.Name)
+	}
+	expected := []string{"dark", "light", "high-contrast", "solarized", "classic"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Expected cycle %v, got %v", expected, names)
+		}
+	}
+	if m.notice != "THEME CLASSIC" {
+		t.Errorf("Expected theme name on the LCD, got %q", m.notice)
+	}
+}
+
+func TestWithCustomTheme(t *testing.T) {
+	custom, err := theme.ParseTOML(`name = "mint"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(WithTheme(custom))
+	if m.theme.Name != "mint" || len(m.themes) != len(theme.Builtins())+1 {
+		t.Fatalf("Expected mint to join the cycle, got %s in %d themes", m.theme.Name, len(m.themes))
+	}
+
+	m = typeKeys(m, runes("t")...)
+	if m.theme.Name != "classic" {
+		t.Errorf("Expected to cycle from mint back to classic, got %s", m.theme.Name)
+	}
+
+	dark, _ := theme.Builtin("dark")
+	m = New(WithTheme(dark))
+	if len(m.themes) != len(theme.Builtins()) {
+		t.Errorf("Expected a built-in theme not to be added twice")
+	}
+	m = typeKeys(m, runes("t")...)
+	if m.theme.Name != "light" {
+		t.Errorf("Expected light after dark, got %s", m.theme.Name)
+	}
+}
+
+func TestThemeFileWithBuiltinName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mine.toml")
+	if err := os.WriteFile(path, []byte("name = \"classic\"\nborder = \"#FF0000\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := theme.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(WithTheme(file), WithConfigFile(filepath.Join(t.TempDir(), "config.toml"), config.Config{Theme: path}))
+	if len(m.themes) != len(theme.Builtins())+1 {
+		t.Fatalf("Expected the theme file to join the cycle, got %d themes", len(m.themes))
+	}
+
+	// Cycle all the way round, back to the file
+	for range theme.Builtins() {
+		m = typeKeys(m, runes("t")...)
+		if m.theme.Path != "" {
+			t.Fatalf("Expected only built-in themes before the file comes round again, got %s", m.theme.Path)
+		}
+	}
+	m = typeKeys(m, runes("t")...)
+	if m.theme.Path != path || m.theme.Border != "#FF0000" {
+		t.Errorf("Expected the theme file back after a full cycle, got %q from %q", m.theme.Name, m.theme.Path)
+	}
+	if saved := m.savedConfig(); saved.Theme != path {
+		t.Errorf("Expected the theme file path to be saved, got %q", saved.Theme)
+	}
+}
+
+func TestMonochromeStatesDoNotRelyOnColor(t *testing.T) {
+	m := New(WithColorProfile(termenv.Ascii))
+	m = resize(m, 80, 40)
+	m.cursorX, m.cursorY = 1, 1 // 8
+
+	if !strings.Contains(m.View(), "[8]") {
+		t.Errorf("Expected the highlighted key in brackets:\n%s", m.View())
+	}
+
+	updatedModel, _ := m.handleButtonPress("5")
+	m = updatedModel.(model)
+	m.pressedX, m.pressedY, m.activationMethod = 1, 2, activationDirectKeyboard
+	if !strings.Contains(m.View(), ">5<") {
+		t.Errorf("Expected the pressed key marked:\n%s", m.View())
+	}
+}
+
+func TestMarksOnlyWhenTheyFit(t *testing.T) {
+	k := marks{"[", "]"}
+	if got := k.wrap("7", 4); got != "[7]" {
+		t.Errorf("Expected [7], got %q", got)
+	}
+	if got := k.wrap("+/-", 4); got != "+/-" {
+		t.Errorf("Expected the bare label when marks do not fit, got %q", got)
+	}
+}
+
+func TestDegradedStylesKeepTextReadable(t *testing.T) {
+	s := newStyles(theme.Classic(), termenv.ANSI)
+	for class, style := range s.classes {
+		if style.GetForeground() == style.GetBackground() {
+			t.Errorf("Class %s draws text in its background color", class)
+		}
+	}
+}
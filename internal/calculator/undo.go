@@ -0,0 +1,86 @@
+package calculator
+
+// maxUndo bounds how many button presses can be undone.
+const maxUndo = 100
+
+// calcState is the part of the model a button press changes.
+type calcState struct {
+	display         string
+	previousDisplay string
+	operator        string
+	operand1        string
+	isOperand2      bool
+	lastButton      string
+	isError         bool
+	entries         []entry
+	recorded        []string // so undoing a press also takes it out of a recording
+}
+
+func (m model) state() calcState {
+	return calcState{
+		display:         m.display,
+		previousDisplay: m.previousDisplay,
+		operator:        m.operator,
+		operand1:        m.operand1,
+		isOperand2:      m.isOperand2,
+		lastButton:      m.lastButton,
+		isError:         m.isError,
+		entries:         m.entries,
+		recorded:        m.recorded,
+	}
+}
+
+func (m *model) restore(s calcState) {
+	m.display = s.display
+	m.previousDisplay = s.previousDisplay
+	m.operator = s.operator
+	m.operand1 = s.operand1
+	m.isOperand2 = s.isOperand2
+	m.lastButton = s.lastButton
+	m.isError = s.isError
+	m.entries = s.entries
+	m.recorded = s.recorded
+}
+
+// pushState returns stack with s on top, dropping the oldest entry once
+// maxUndo is reached. The stack is copied so models that share a backing
+// array never see each other's history.
+func pushState(stack []calcState, s calcState) []calcState {
+	if len(stack) >= maxUndo {
+		stack = stack[len(stack)-maxUndo+1:]
+	}
+	return append(stack[:len(stack):len(stack)], s)
+}
+
+// withoutRecorded returns stack with no recorded presses in its states.
+func withoutRecorded(stack []calcState) []calcState {
+	stack = append([]calcState(nil), stack...)
+	for i := range stack {
+		stack[i].recorded = nil
+	}
+	return stack
+}
+
+// undo steps back to the state before the last button press.
+func (m model) undo() model {
+	if len(m.undoStack) == 0 {
+		return m
+	}
+	m.redoStack = pushState(m.redoStack, m.state())
+	m.restore(m.undoStack[len(m.undoStack)-1])
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.scroll = 0
+	return m
+}
+
+// redo reapplies the last undone button press.
+func (m model) redo() model {
+	if len(m.redoStack) == 0 {
+		return m
+	}
+	m.undoStack = pushState(m.undoStack, m.state())
+	m.restore(m.redoStack[len(m.redoStack)-1])
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.scroll = 0
+	return m
+}
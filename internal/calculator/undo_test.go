@@ -0,0 +1,121 @@
+package calculator
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoRestoresStateExactly(t *testing.T) {
+	m := New()
+
+	press := func(button string) {
+		updatedModel, _ := m.handleButtonPress(button)
+		m = updatedModel.(model)
+	}
+
+	press("1")
+	press("2")
+	press("+")
+	before := m.state()
+	press("x") // mis-typed operator
+
+	m = m.undo()
+	if !reflect.DeepEqual(m.state(), before) {
+		t.Errorf("Expected state %+v after undo, got %+v", before, m.state())
+	}
+	if m.operand1 != "12" || m.operator != "+" || !m.isOperand2 || m.previousDisplay != "12 +" {
+		t.Errorf("Unexpected state after undo: %+v", m.state())
+	}
+
+	press("3")
+	press("=")
+	if m.display != "15" {
+		t.Errorf("Expected display 15, got %s", m.display)
+	}
+}
+
+func TestUndoRedoSequence(t *testing.T) {
+	m := New()
+
+	var states []calcState
+	for _, btn := range []string{"5", "x", "4", "="} {
+		states = append(states, m.state())
+		updatedModel, _ := m.handleButtonPress(btn)
+		m = updatedModel.(model)
+	}
+	final := m.state()
+
+	for i := len(states) - 1; i >= 0; i-- {
+		m = m.undo()
+		if !reflect.DeepEqual(m.state(), states[i]) {
+			t.Errorf("Undo step %d: expected %+v, got %+v", i, states[i], m.state())
+		}
+	}
+
+	// Nothing left to undo
+	m = m.undo()
+	if !reflect.DeepEqual(m.state(), states[0]) {
+		t.Errorf("Undo past the start should be a no-op")
+	}
+
+	for i := 1; i < len(states); i++ {
+		m = m.redo()
+		if !reflect.DeepEqual(m.state(), states[i]) {
+			t.Errorf("Redo step %d: expected %+v, got %+v", i, states[i], m.state())
+		}
+	}
+	m = m.redo()
+	if !reflect.DeepEqual(m.state(), final) {
+		t.Errorf("Expected final state %+v after redo, got %+v", final, m.state())
+	}
+}
+
+func TestPressClearsRedo(t *testing.T) {
+	m := New()
+	updatedModel, _ := m.handleButtonPress("7")
+	m = updatedModel.(model)
+	m = m.undo()
+
+	updatedModel, _ = m.handleButtonPress("8")
+	m = updatedModel.(model)
+	if len(m.redoStack) != 0 {
+		t.Errorf("Expected redo stack to be cleared by a new press")
+	}
+	m = m.redo()
+	if m.display != "8" {
+		t.Errorf("Expected display 8, got %s", m.display)
+	}
+}
+
+func TestUndoStackIsBounded(t *testing.T) {
+	m := New()
+	for i := 0; i < maxUndo+20; i++ {
+		updatedModel, _ := m.handleButtonPress("1")
+		m = updatedModel.(model)
+	}
+	if len(m.undoStack) != maxUndo {
+		t.Errorf("Expected %d undo entries, got %d", maxUndo, len(m.undoStack))
+	}
+}
+
+func TestUndoKeys(t *testing.T) {
+	m := New()
+	for _, r := range "9+" {
+		updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updatedModel.(model)
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updatedModel.(model)
+	if m.operator != "" || m.previousDisplay != "" || m.display != "9" {
+		t.Errorf("Expected ctrl+z to undo the operator, got %+v", m.state())
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	m = updatedModel.(model)
+	if m.operator != "+" || m.previousDisplay != "9 +" {
+		t.Errorf("Expected ctrl+y to redo the operator, got %+v", m.state())
+	}
+}
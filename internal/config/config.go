@@ -0,0 +1,165 @@
+// Package config reads the calculator's settings file, config.toml in the
+// user's config directory.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+	"github.com/dmisiuk/goose-tui-calculator/internal/tomlfile"
+)
+
+// dirName is the calculator's directory under the user config directory.
+const dirName = "goose-calculator"
+
+// Config holds the calculator's settings. Settings left out of the config
+// file keep their defaults.
+type Config struct {
+	Theme        string `toml:"theme"`         // built-in theme name or theme file
+	Color        string `toml:"color"`         // auto, truecolor, 256, 16 or none
+	Sound        bool   `toml:"sound"`         // key clicks and terminal bell
+	Precision    int    `toml:"precision"`     // decimal places, -1 for as many as needed
+	Rounding     string `toml:"rounding"`      // half-up, half-even, down or up
+	NumberFormat string `toml:"number_format"` // plain, grouped or scientific
+	Angle        string `toml:"angle"`         // degrees, radians or gradians
+	Layout       string `toml:"layout"`        // keypad layout file, empty for the classic keypad
+	Segments     bool   `toml:"segments"`
+	Keypad       bool   `toml:"keypad"`
+	Wrap         bool   `toml:"wrap"`
+
+	// Keys replaces the keys of bindings by name, e.g. quit = ["ctrl+q"]
+	Keys map[string][]string `toml:"keys,omitempty"`
+}
+
+// Default returns the settings used when there is no config file.
+func Default() Config {
+	return Config{
+		Theme:        "classic",
+		Color:        "auto",
+		Sound:        true,
+		Precision:    numfmt.Auto,
+		Rounding:     numfmt.HalfUp.String(),
+		NumberFormat: numfmt.Plain.String(),
+		Angle:        expr.Degrees.String(),
+	}
+}
+
+// Format returns the number format described by the precision, rounding
+// and number format settings.
+func (c Config) Format() (numfmt.Format, error) {
+	if c.Precision < numfmt.Auto || c.Precision > numfmt.MaxDecimals {
+		return numfmt.Format{}, fmt.Errorf("precision %d out of range, use -1 for as many as needed or 0-%d", c.Precision, numfmt.MaxDecimals)
+	}
+	rounding, err := numfmt.ParseRounding(c.Rounding)
+	if err != nil {
+		return numfmt.Format{}, err
+	}
+	notation, err := numfmt.ParseNotation(c.NumberFormat)
+	if err != nil {
+		return numfmt.Format{}, err
+	}
+	return numfmt.Format{Decimals: c.Precision, Rounding: rounding, Notation: notation}, nil
+}
+
+// AngleUnit returns the angle unit setting.
+func (c Config) AngleUnit() (expr.Angle, error) {
+	return expr.ParseAngle(c.Angle)
+}
+
+// Write encodes c as a TOML config file.
+func (c Config) Write(w io.Writer) error {
+	return encode(w, c)
+}
+
+// encode writes v to w as TOML, without indenting tables.
+func encode(w io.Writer, v any) error {
+	enc := toml.NewEncoder(w)
+	enc.Indent = ""
+	return enc.Encode(v)
+}
+
+// writeFile writes v as TOML to the file at path, creating its directory.
+func writeFile(path string, v any) error {
+	var b bytes.Buffer
+	if err := encode(&b, v); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// besidePath returns the path of the file called name kept next to the
+// config file at configPath.
+func besidePath(configPath, name string) string {
+	return filepath.Join(filepath.Dir(configPath), name)
+}
+
+// Dir returns the calculator's config directory, under $XDG_CONFIG_HOME
+// or ~/.config on Linux.
+func Dir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, dirName), nil
+}
+
+// Path returns the path of the config file.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Load reads the config file at path.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	c, err := Parse(string(data))
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes c to the config file at path, creating its directory.
+func Save(path string, c Config) error {
+	return writeFile(path, c)
+}
+
+// LoadDefault reads the config file from the config directory. A missing
+// file leaves every setting at its default.
+func LoadDefault() (Config, string, error) {
+	path, err := Path()
+	if err != nil {
+		return Default(), "", nil
+	}
+	c, err := Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(), path, nil
+	}
+	return c, path, err
+}
+
+// Parse decodes a TOML config on top of the defaults.
+func Parse(data string) (Config, error) {
+	c := Default()
+	if err := tomlfile.Decode(data, &c); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+func TestParseKeys(t *testing.T) {
+	c, err := Parse(`
+[keys]
+quit = ["ctrl+q"]
+clear = ["delete", "c"]
+help = []
+`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	expected := map[string][]string{
+		"quit":  {"ctrl+q"},
+		"clear": {"delete", "c"},
+		"help":  {},
+	}
+	if !reflect.DeepEqual(c.Keys, expected) {
+		t.Errorf("Expected keys %v, got %v", expected, c.Keys)
+	}
+}
+
+func TestParseRejectsUnknownFields(t *testing.T) {
+	if _, err := Parse(`colour = "red"`); err == nil || !strings.Contains(err.Error(), "unknown fields: colour") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestLoadDefault(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	c, path, err := LoadDefault()
+	if err != nil || c.Keys != nil {
+		t.Fatalf("Expected an empty config without a file, got %+v, %v", c, err)
+	}
+	if path != filepath.Join(dir, "goose-calculator", "config.toml") {
+		t.Errorf("Unexpected config path %s", path)
+	}
+
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte("[keys]\nquit = [\"Q\"]\n"), 0o644)
+	c, _, err = LoadDefault()
+	if err != nil || c.Keys["quit"][0] != "Q" {
+		t.Errorf("Expected keys from the config file, got %+v, %v", c, err)
+	}
+
+	os.WriteFile(path, []byte("[keys\n"), 0o644)
+	if _, _, err := LoadDefault(); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Expected a parse error naming the file, got %v", err)
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	c, err := Parse(`
+theme = "dark"
+sound = false
+precision = 2
+number_format = "grouped"
+`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	expected := Default()
+	expected.Theme = "dark"
+	expected.Sound = false
+	expected.Precision = 2
+	expected.NumberFormat = "grouped"
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("Expected settings left out to keep their defaults:\n%+v\ngot\n%+v", expected, c)
+	}
+
+	f, err := c.Format()
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	if f != (numfmt.Format{Decimals: 2, Rounding: numfmt.HalfUp, Notation: numfmt.Grouped}) {
+		t.Errorf("Unexpected number format %+v", f)
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{`precision = 16`, "precision 16 out of range"},
+		{`precision = -2`, "precision -2 out of range"},
+		{`rounding = "nearest"`, `unknown rounding "nearest"`},
+		{`number_format = "roman"`, `unknown number format "roman"`},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.config)
+		if err != nil {
+			t.Fatalf("Parse(%s) returned error: %v", tt.config, err)
+		}
+		if _, err := c.Format(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%s: expected error %q, got %v", tt.config, tt.expected, err)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	c := Default()
+	c.Wrap = true
+	c.Keys = map[string][]string{"quit": {"ctrl+q"}}
+
+	var b strings.Builder
+	if err := c.Write(&b); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if !strings.Contains(b.String(), "number_format = \"plain\"") {
+		t.Errorf("Expected every setting in the output, got\n%s", b.String())
+	}
+	read, err := Parse(b.String())
+	if err != nil {
+		t.Fatalf("Parse returned error: %v\n%s", err, b.String())
+	}
+	if !reflect.DeepEqual(read, c) {
+		t.Errorf("Expected %+v after a round trip, got %+v", c, read)
+	}
+}
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goose-calculator", "config.toml")
+	c := Default()
+	c.Angle = "radians"
+	if err := Save(path, c); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	read, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if a, err := read.AngleUnit(); err != nil || a != expr.Radians {
+		t.Errorf("Expected radians after saving, got %v, %v", a, err)
+	}
+}
+
+func TestMacrosRoundTrip(t *testing.T) {
+	path := MacrosPath(filepath.Join(t.TempDir(), "calc", "config.toml"))
+	m, err := LoadMacros(path)
+	if err != nil || len(m) != 0 {
+		t.Fatalf("Expected no macros without a file, got %v, %v", m, err)
+	}
+
+	saved := Macros{"1": {"multiply", "digit-1", "decimal", "digit-2", "equals"}, "2": {"add", "-12.5", "equals"}}
+	if err := SaveMacros(path, saved); err != nil {
+		t.Fatalf("SaveMacros returned error: %v", err)
+	}
+	loaded, err := LoadMacros(path)
+	if err != nil || !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("Expected %v, got %v, %v", saved, loaded, err)
+	}
+}
+
+func TestLoadMacrosRejectsBadEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macros.toml")
+	os.WriteFile(path, []byte("[macros]\n0 = [\"add\"]\n2 = [\"sqrt\"]\n"), 0o644)
+	_, err := LoadMacros(path)
+	if err == nil || !strings.Contains(err.Error(), `macro slot "0"`) || !strings.Contains(err.Error(), `unknown action "sqrt"`) {
+		t.Errorf("Expected slot and action errors, got %v", err)
+	}
+
+	os.WriteFile(path, []byte("[macro]\n1 = [\"add\"]\n"), 0o644)
+	if _, err := LoadMacros(path); err == nil || !strings.Contains(err.Error(), "unknown fields: macro") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestDefinitionsRoundTrip(t *testing.T) {
+	path := DefinitionsPath(filepath.Join(t.TempDir(), "calc", "config.toml"))
+	env, err := LoadDefinitions(path, expr.Env{})
+	if err != nil || env.Vars != nil || env.Funcs != nil {
+		t.Fatalf("Expected no definitions without a file, got %+v, %v", env, err)
+	}
+
+	env.Vars = map[string]float64{"A": 42, "ans": 7}
+	d, _, _ := expr.ParseDefinition("vat(x) = x * 1.2")
+	env, _, _ = env.Define(d)
+	if err := SaveDefinitions(path, env); err != nil {
+		t.Fatalf("SaveDefinitions returned error: %v", err)
+	}
+
+	loaded, err := LoadDefinitions(path, expr.Env{Angle: expr.Radians})
+	if err != nil {
+		t.Fatalf("LoadDefinitions returned error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Vars, map[string]float64{"A": 42}) || loaded.Angle != expr.Radians {
+		t.Errorf("Expected A = 42 without ans, got %+v", loaded)
+	}
+	if v, err := loaded.Eval("vat(A)"); err != nil || v != 50.4 {
+		t.Errorf("Expected vat(A) = 50.4, got %v, %v", v, err)
+	}
+}
+
+func TestLoadDefinitionsRejectsBadEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "definitions.toml")
+	os.WriteFile(path, []byte("functions = [\"rate = 2\", \"f(x) = x + nope\"]\n[variables]\nsin = 1\n"), 0o644)
+	_, err := LoadDefinitions(path, expr.Env{})
+	for _, expected := range []string{"variable sin", `function "rate = 2": not a function`, `unknown name "nope"`} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected an error containing %q, got %v", expected, err)
+		}
+	}
+
+	os.WriteFile(path, []byte("[variable]\nA = 1\n"), 0o644)
+	if _, err := LoadDefinitions(path, expr.Env{}); err == nil || !strings.Contains(err.Error(), "unknown fields: variable") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/tomlfile"
+)
+
+// Definitions are the user's variables and functions.
+type Definitions struct {
+	Variables map[string]float64 `toml:"variables,omitempty"`
+	Functions []string           `toml:"functions,omitempty"` // e.g. "vat(x) = x * 1.2"
+}
+
+// DefinitionsPath returns the path of the definitions file kept next to
+// the config file at configPath.
+func DefinitionsPath(configPath string) string {
+	return besidePath(configPath, "definitions.toml")
+}
+
+// LoadDefinitions reads the definitions file at path and adds its
+// variables and functions to env. A missing file adds nothing.
+func LoadDefinitions(path string, env expr.Env) (expr.Env, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return env, nil
+	}
+	if err != nil {
+		return env, err
+	}
+	var d Definitions
+	if err := tomlfile.Decode(string(data), &d); err != nil {
+		return env, fmt.Errorf("%s: %w", path, err)
+	}
+	env, err = d.apply(env)
+	if err != nil {
+		return env, fmt.Errorf("%s: %w", path, err)
+	}
+	return env, nil
+}
+
+// apply adds the variables and then the functions of d to env, in order,
+// so a function can use those defined before it.
+func (d Definitions) apply(env expr.Env) (expr.Env, error) {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(d.Variables)) {
+		if err := expr.CheckName(name); err != nil {
+			errs = append(errs, fmt.Errorf("variable %s: %w", name, err))
+			continue
+		}
+		env.Vars = maps.Clone(env.Vars)
+		if env.Vars == nil {
+			env.Vars = map[string]float64{}
+		}
+		env.Vars[name] = d.Variables[name]
+	}
+	for _, line := range d.Functions {
+		def, ok, err := expr.ParseDefinition(line)
+		if err == nil && (!ok || !def.IsFunc()) {
+			err = errors.New("not a function definition")
+		}
+		if err == nil {
+			env, _, err = env.Define(def)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("function %q: %w", line, err))
+		}
+	}
+	return env, errors.Join(errs...)
+}
+
+// DefinitionsOf returns the variables and functions of env, leaving out
+// ans.
+func DefinitionsOf(env expr.Env) Definitions {
+	var d Definitions
+	for name, v := range env.Vars {
+		if name == "ans" {
+			continue
+		}
+		if d.Variables == nil {
+			d.Variables = map[string]float64{}
+		}
+		d.Variables[name] = v
+	}
+	for _, name := range slices.Sorted(maps.Keys(env.Funcs)) {
+		d.Functions = append(d.Functions, env.Funcs[name].String())
+	}
+	return d
+}
+
+// SaveDefinitions writes the variables and functions of env to the
+// definitions file at path, creating its directory.
+func SaveDefinitions(path string, env expr.Env) error {
+	return writeFile(path, DefinitionsOf(env))
+}
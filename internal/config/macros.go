@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/keypad"
+	"github.com/dmisiuk/goose-tui-calculator/internal/tomlfile"
+)
+
+// MacroSlots are the slots macros are stored in.
+const MacroSlots = "123456789"
+
+// Macros are recorded button presses by slot, "1" to "9". Each press is a
+// keypad action id such as digit-1 or multiply, or a value that was
+// pasted or recalled, such as 12.5.
+type Macros map[string][]string
+
+// macrosFile is the layout of macros.toml.
+type macrosFile struct {
+	Macros Macros `toml:"macros"`
+}
+
+// MacrosPath returns the path of the macros file kept next to the config
+// file at configPath.
+func MacrosPath(configPath string) string {
+	return besidePath(configPath, "macros.toml")
+}
+
+// LoadMacros reads the macros file at path. A missing file has no macros.
+func LoadMacros(path string) (Macros, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Macros{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f macrosFile
+	if err := tomlfile.Decode(string(data), &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := f.Macros.check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Macros == nil {
+		f.Macros = Macros{}
+	}
+	return f.Macros, nil
+}
+
+// check reports slots other than 1-9 and unknown actions.
+func (m Macros) check() error {
+	var errs []error
+	for _, slot := range slices.Sorted(maps.Keys(m)) {
+		if len(slot) != 1 || !strings.Contains(MacroSlots, slot) {
+			errs = append(errs, fmt.Errorf("macro slot %q, use 1-9", slot))
+			continue
+		}
+		for _, action := range m[slot] {
+			if _, ok := keypad.Actions[action]; !ok && !expr.IsNumber(action) {
+				errs = append(errs, fmt.Errorf("macro %s: unknown action %q", slot, action))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SaveMacros writes m to the macros file at path, creating its directory.
+func SaveMacros(path string, m Macros) error {
+	return writeFile(path, macrosFile{Macros: m})
+}
@@ -0,0 +1,203 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// maxDepth is how deeply user functions may call each other.
+const maxDepth = 64
+
+// Definition names a value, as in "rate = 7.5", or a function of its
+// parameters, as in "vat(x) = x * 1.2".
+type Definition struct {
+	Name   string
+	Params []string // empty for a variable
+	Body   string
+}
+
+// IsFunc reports whether d defines a function.
+func (d Definition) IsFunc() bool { return len(d.Params) > 0 }
+
+func (d Definition) String() string {
+	if !d.IsFunc() {
+		return d.Name + " = " + d.Body
+	}
+	return fmt.Sprintf("%s(%s) = %s", d.Name, strings.Join(d.Params, ", "), d.Body)
+}
+
+// CheckName reports whether name can be given to a variable or function:
+// it must be made of letters and must not be ans or a built-in function.
+func CheckName(name string) error {
+	switch {
+	case !isName(name):
+		return fmt.Errorf("bad name %q, use letters only", name)
+	case name == "ans":
+		return fmt.Errorf("ans is the last result and cannot be set")
+	case functions[name] != nil:
+		return fmt.Errorf("%s is a built-in function", name)
+	}
+	return nil
+}
+
+// isName reports whether s is a single word of letters, whether or not
+// it is free to be defined.
+func isName(s string) bool {
+	toks, err := lex(s)
+	return err == nil && len(toks) == 2 && toks[0].kind == tokName
+}
+
+// isSignature reports whether s has the shape of a name or of a function
+// with its parameters, such as rate or vat(x), so that lines like 1+2=
+// and cos(0) = 1 are not taken for definitions.
+func isSignature(s string) bool {
+	name, params, isFunc := strings.Cut(s, "(")
+	if !isName(name) {
+		return false
+	}
+	if !isFunc {
+		return true
+	}
+	params, closed := strings.CutSuffix(strings.TrimSpace(params), ")")
+	if !closed {
+		return false
+	}
+	if strings.TrimSpace(params) == "" {
+		return true // reported as a missing parameter
+	}
+	for _, param := range strings.Split(params, ",") {
+		if !isName(param) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseDefinition reads a line of the form name = expression or
+// name(a, b) = expression. ok is false when line is not a definition: it
+// has no "=", or what comes before it is not a name or a function with
+// its parameters.
+func ParseDefinition(line string) (d Definition, ok bool, err error) {
+	lhs, body, found := strings.Cut(line, "=")
+	lhs = strings.TrimSpace(lhs)
+	if !found || !isSignature(lhs) {
+		return Definition{}, false, nil
+	}
+	d.Body = strings.TrimSpace(body)
+	if d.Body == "" {
+		return Definition{}, true, fmt.Errorf("missing expression after =")
+	}
+
+	name, params, isFunc := strings.Cut(lhs, "(")
+	d.Name = strings.TrimSpace(name)
+	if err := CheckName(d.Name); err != nil {
+		return Definition{}, true, err
+	}
+	if !isFunc {
+		return d, true, nil
+	}
+	params = strings.TrimSuffix(strings.TrimSpace(params), ")")
+	for _, param := range strings.Split(params, ",") {
+		param = strings.TrimSpace(param)
+		if err := CheckName(param); err != nil {
+			return Definition{}, true, fmt.Errorf("parameter of %s: %w", d.Name, err)
+		}
+		for _, p := range d.Params {
+			if p == param {
+				return Definition{}, true, fmt.Errorf("parameter %s of %s repeated", param, d.Name)
+			}
+		}
+		d.Params = append(d.Params, param)
+	}
+	return d, true, nil
+}
+
+// Define returns e with d added, replacing any variable or function of
+// the same name. A variable's expression is evaluated now and its value
+// is returned; a function's expression is checked with every parameter
+// set to 1 and evaluated at each call.
+func (e Env) Define(d Definition) (Env, float64, error) {
+	if err := CheckName(d.Name); err != nil {
+		return e, 0, err
+	}
+	e.Vars, e.Funcs = maps.Clone(e.Vars), maps.Clone(e.Funcs)
+	if e.Vars == nil {
+		e.Vars = map[string]float64{}
+	}
+	if e.Funcs == nil {
+		e.Funcs = map[string]Definition{}
+	}
+
+	if !d.IsFunc() {
+		v, err := e.Eval(d.Body)
+		if err != nil {
+			return e, 0, err
+		}
+		delete(e.Funcs, d.Name)
+		e.Vars[d.Name] = v
+		return e, v, nil
+	}
+
+	check := e
+	check.Vars = maps.Clone(e.Vars)
+	for _, p := range d.Params {
+		check.Vars[p] = 1
+	}
+	if _, err := check.Eval(d.Body); err != nil && !isArithmetic(err) {
+		return e, 0, err
+	}
+	delete(e.Vars, d.Name)
+	e.Funcs[d.Name] = d
+	return e, 0, nil
+}
+
+// isArithmetic reports whether err comes from the values an expression
+// was evaluated with rather than from the expression itself.
+func isArithmetic(err error) bool {
+	return errors.Is(err, ErrDivisionByZero) || errors.Is(err, ErrOverflow) || errors.Is(err, ErrDomain)
+}
+
+// call evaluates the user function d named by t with the arguments in the
+// parentheses that follow.
+func (p *parser) call(t token, d Definition) (float64, error) {
+	if _, ok := p.accept("("); !ok {
+		return 0, &SyntaxError{Pos: p.peek().pos, Msg: fmt.Sprintf("missing '(' after %s", t.text)}
+	}
+	var args []float64
+	for {
+		v, err := p.expression()
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, v)
+		if _, ok := p.accept(","); !ok {
+			break
+		}
+	}
+	if _, ok := p.accept(")"); !ok {
+		return 0, &SyntaxError{Pos: p.peek().pos, Msg: "missing ')'"}
+	}
+	if len(args) != len(d.Params) {
+		return 0, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("%s takes %d arguments, got %d", t.text, len(d.Params), len(args))}
+	}
+	if p.env.depth >= maxDepth {
+		return 0, fmt.Errorf("%s: %w", t.text, ErrRecursion)
+	}
+
+	env := p.env
+	env.depth++
+	env.Vars = maps.Clone(env.Vars)
+	if env.Vars == nil {
+		env.Vars = map[string]float64{}
+	}
+	for i, param := range d.Params {
+		env.Vars[param] = args[i]
+	}
+	v, err := env.Eval(d.Body)
+	if err != nil {
+		return 0, fmt.Errorf("in %s: %w", t.text, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,121 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDefinition(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Definition
+	}{
+		{"rate = 7.5 / 100", Definition{Name: "rate", Body: "7.5 / 100"}},
+		{"A=42", Definition{Name: "A", Body: "42"}},
+		{"vat(x) = x * 1.2", Definition{Name: "vat", Params: []string{"x"}, Body: "x * 1.2"}},
+		{"avg(a, b) = (a + b) / 2", Definition{Name: "avg", Params: []string{"a", "b"}, Body: "(a + b) / 2"}},
+	}
+	for _, tt := range tests {
+		d, ok, err := ParseDefinition(tt.line)
+		if !ok || err != nil || !reflect.DeepEqual(d, tt.expected) {
+			t.Errorf("ParseDefinition(%q) = %+v, %v, %v, expected %+v", tt.line, d, ok, err, tt.expected)
+		}
+	}
+	if d, _, _ := ParseDefinition("avg(a,b)=(a+b)/2"); d.String() != "avg(a, b) = (a+b)/2" {
+		t.Errorf("Unexpected definition text %q", d.String())
+	}
+
+	for _, line := range []string{"1 + 2", "1+2=", "cos(0) = 1", "2 = 3", "f(x = x"} {
+		if _, ok, err := ParseDefinition(line); ok || err != nil {
+			t.Errorf("Expected %q not to be a definition, got %v, %v", line, ok, err)
+		}
+	}
+}
+
+func TestParseDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		line, expected string
+	}{
+		{"rate =", "missing expression"},
+		{"ans = 3", "ans is the last result"},
+		{"sin(x) = x", "sin is a built-in function"},
+		{"f() = 1", "parameter of f"},
+		{"f(a, a) = a", "parameter a of f repeated"},
+	}
+	for _, tt := range tests {
+		_, ok, err := ParseDefinition(tt.line)
+		if !ok || err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("ParseDefinition(%q): expected an error containing %q, got %v", tt.line, tt.expected, err)
+		}
+	}
+}
+
+// define adds each definition line to env.
+func define(t *testing.T, env Env, lines ...string) Env {
+	t.Helper()
+	for _, line := range lines {
+		d, _, err := ParseDefinition(line)
+		if err != nil {
+			t.Fatalf("ParseDefinition(%q): %v", line, err)
+		}
+		if env, _, err = env.Define(d); err != nil {
+			t.Fatalf("Define(%q): %v", line, err)
+		}
+	}
+	return env
+}
+
+func TestUserFunctions(t *testing.T) {
+	env := define(t, Env{}, "vat(x) = x * 1.2", "avg(a, b) = (a + b) / 2", "rate = 10", "net(x) = x - x * rate%", "x = 5")
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"vat(100)", 120},
+		{"vat(50) + 1", 61},
+		{"avg(2, 4)", 3},
+		{"avg(vat(10), 0)", 6},
+		{"net(200)", 180},
+		{"2x3", 6},
+		{"x * 2", 10},
+		{"vat(x)", 6},
+	}
+	for _, tt := range tests {
+		got, err := env.Eval(tt.input)
+		if err != nil || got != tt.expected {
+			t.Errorf("Eval(%q) = %v, %v, expected %v", tt.input, got, err, tt.expected)
+		}
+	}
+
+	var syntaxErr *SyntaxError
+	if _, err := env.Eval("avg(1)"); !errors.As(err, &syntaxErr) || syntaxErr.Msg != "avg takes 2 arguments, got 1" {
+		t.Errorf("Expected an argument count error, got %v", err)
+	}
+	if _, err := env.Eval("vat 2"); !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected a missing '(' error, got %v", err)
+	}
+}
+
+func TestDefineReplacesAndChecks(t *testing.T) {
+	env := define(t, Env{}, "f = 3", "f(y) = y + 1")
+	if _, ok := env.Vars["f"]; ok {
+		t.Errorf("Expected the function to replace the variable")
+	}
+	env = define(t, env, "g(y) = 1 / (y - 1)")
+	if _, err := env.Eval("g(1)"); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Expected division by zero from g(1), got %v", err)
+	}
+
+	d, _, _ := ParseDefinition("h(y) = y + nope")
+	if _, _, err := env.Define(d); !errors.Is(err, ErrSyntax) {
+		t.Errorf("Expected an unknown name to be reported, got %v", err)
+	}
+
+	// Functions defined in terms of each other do not loop forever
+	env = define(t, env, "a(y) = y", "b(y) = a(y)", "a(y) = b(y)")
+	if _, err := env.Eval("a(1)"); !errors.Is(err, ErrRecursion) {
+		t.Errorf("Expected ErrRecursion, got %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+// Package expr parses and evaluates arithmetic expressions such as
+// "(12.5 * 4) - 3" with the usual operator precedence.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrSyntax is wrapped by every *SyntaxError.
+	ErrSyntax = errors.New("syntax error")
+	// ErrDivisionByZero is returned when an expression divides by zero.
+	ErrDivisionByZero = errors.New("division by zero")
+	// ErrOverflow is returned when a result is too large to represent.
+	ErrOverflow = errors.New("overflow")
+	// ErrDomain is returned when a function is given an argument outside
+	// its domain, such as asin(2).
+	ErrDomain = errors.New("domain error")
+	// ErrRecursion is returned when user functions call each other too
+	// deeply, as a function defined in terms of itself would.
+	ErrRecursion = errors.New("calls nested too deeply")
+)
+
+// SyntaxError reports why and where an expression could not be parsed.
+type SyntaxError struct {
+	Pos int // 1-based column of the offending input
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.Pos)
+}
+
+func (e *SyntaxError) Unwrap() error { return ErrSyntax }
+
+// Angle is the unit trigonometric functions take and return angles in.
+type Angle int
+
+const (
+	Degrees Angle = iota
+	Radians
+	Gradians
+)
+
+var angleNames = []string{"degrees", "radians", "gradians"}
+
+func (a Angle) String() string { return angleNames[a] }
+
+// Angles returns every angle unit.
+func Angles() []Angle {
+	return []Angle{Degrees, Radians, Gradians}
+}
+
+// ParseAngle returns the angle unit called name.
+func ParseAngle(name string) (Angle, error) {
+	for i, n := range angleNames {
+		if n == name {
+			return Angle(i), nil
+		}
+	}
+	return Degrees, fmt.Errorf("unknown angle unit %q, use %s", name, strings.Join(angleNames, ", "))
+}
+
+// toRadians converts v from the unit a to radians.
+func (a Angle) toRadians(v float64) float64 {
+	switch a {
+	case Degrees:
+		return v * math.Pi / 180
+	case Gradians:
+		return v * math.Pi / 200
+	}
+	return v
+}
+
+// fromRadians converts v in radians to the unit a.
+func (a Angle) fromRadians(v float64) float64 {
+	switch a {
+	case Degrees:
+		return v * 180 / math.Pi
+	case Gradians:
+		return v * 200 / math.Pi
+	}
+	return v
+}
+
+// Env holds the settings, variables and user functions an expression is
+// evaluated with.
+type Env struct {
+	Angle Angle
+	Vars  map[string]float64    // e.g. ans, the last result
+	Funcs map[string]Definition // user functions by name, see Define
+
+	depth int // user function calls being evaluated
+}
+
+// Eval evaluates s in degrees. Operators are + - * / with x, × and ÷
+// accepted as aliases, a postfix % divides by 100, and parentheses group.
+// The functions sin, cos, tan, asin, acos and atan take one argument in
+// parentheses; user functions take theirs separated by commas.
+func Eval(s string) (float64, error) {
+	return Env{}.Eval(s)
+}
+
+// Eval evaluates s like the package function Eval, with the settings and
+// variables of e.
+func (e Env) Eval(s string) (float64, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return 0, err
+	}
+	p := &parser{toks: toks, env: e}
+	v, err := p.expression()
+	if err != nil {
+		return 0, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		if t.text == ")" {
+			return 0, &SyntaxError{Pos: t.pos, Msg: "extra ')'"}
+		}
+		return 0, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+	}
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, ErrOverflow
+	}
+	return v, nil
+}
+
+// IsNumber reports whether s is a single plain number, optionally signed.
+func IsNumber(s string) bool {
+	toks, err := lex(s)
+	if err != nil {
+		return false
+	}
+	if toks[0].text == "-" || toks[0].text == "+" {
+		toks = toks[1:]
+	}
+	return len(toks) == 2 && toks[0].kind == tokNumber
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokOperator
+	tokName
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+// operatorAliases maps accepted operator spellings to canonical ones.
+var operatorAliases = map[rune]string{
+	'+': "+", '-': "-", '−': "-",
+	'*': "*", 'x': "*", 'X': "*", '×': "*",
+	'/': "/", '÷': "/",
+	'%': "%", '(': "(", ')': ")", ',': ",",
+}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// Exponent, e.g. 1.5e-3
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for j < len(runes) && unicode.IsDigit(runes[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			text := string(runes[start:i])
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, &SyntaxError{Pos: start + 1, Msg: fmt.Sprintf("bad number %q", text)}
+			}
+			toks = append(toks, token{kind: tokNumber, text: text, value: v, pos: start + 1})
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			text := string(runes[start:i])
+			if op, ok := operatorAliases[r]; ok && len(text) == 1 && endsOperand(toks) {
+				// A lone x after a value is the multiplication sign, as
+				// in 2x3, and elsewhere a name, as in vat(x)
+				toks = append(toks, token{kind: tokOperator, text: op, pos: start + 1})
+				continue
+			}
+			toks = append(toks, token{kind: tokName, text: text, pos: start + 1})
+		default:
+			op, ok := operatorAliases[r]
+			if !ok {
+				return nil, &SyntaxError{Pos: i + 1, Msg: fmt.Sprintf("bad char %q", r)}
+			}
+			toks = append(toks, token{kind: tokOperator, text: op, pos: i + 1})
+			i++
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(runes) + 1}), nil
+}
+
+// endsOperand reports whether toks end with a complete value, so what
+// follows is an operator.
+func endsOperand(toks []token) bool {
+	if len(toks) == 0 {
+		return false
+	}
+	last := toks[len(toks)-1]
+	return last.kind == tokNumber || last.kind == tokName || last.text == ")" || last.text == "%"
+}
+
+type parser struct {
+	toks []token
+	pos  int
+	env  Env
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// expression := term (("+" | "-") term)*
+func (p *parser) expression() (float64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return v, nil
+		}
+		rhs, err := p.term()
+		if err != nil {
+			return 0, err
+		}
+		if op == "+" {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+}
+
+// term := unary (("*" | "/") unary)*
+func (p *parser) term() (float64, error) {
+	v, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return v, nil
+		}
+		rhs, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		if op == "*" {
+			v *= rhs
+		} else {
+			if rhs == 0 {
+				return 0, ErrDivisionByZero
+			}
+			v /= rhs
+		}
+	}
+}
+
+// unary := ("+" | "-") unary | postfix
+func (p *parser) unary() (float64, error) {
+	if op, ok := p.accept("+", "-"); ok {
+		v, err := p.unary()
+		if op == "-" {
+			v = -v
+		}
+		return v, err
+	}
+	return p.postfix()
+}
+
+// postfix := primary "%"*
+func (p *parser) postfix() (float64, error) {
+	v, err := p.primary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, ok := p.accept("%"); !ok {
+			return v, nil
+		}
+		v /= 100
+	}
+}
+
+// primary := number | "(" expression ")" | name "(" expression ")"
+func (p *parser) primary() (float64, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokNumber:
+		return t.value, nil
+	case t.text == "(":
+		return p.parenthesized()
+	case t.kind == tokName:
+		return p.name(t)
+	case t.kind == tokEOF:
+		return 0, &SyntaxError{Pos: t.pos, Msg: "no number"}
+	default:
+		return 0, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+	}
+}
+
+// parenthesized := expression ")", after the opening parenthesis
+func (p *parser) parenthesized() (float64, error) {
+	v, err := p.expression()
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := p.accept(")"); !ok {
+		return 0, &SyntaxError{Pos: p.peek().pos, Msg: "missing ')'"}
+	}
+	return v, nil
+}
+
+// functions are the functions an expression can call, given the angle
+// unit.
+var functions = map[string]func(a Angle, v float64) float64{
+	"sin":  func(a Angle, v float64) float64 { return math.Sin(a.toRadians(v)) },
+	"cos":  func(a Angle, v float64) float64 { return math.Cos(a.toRadians(v)) },
+	"tan":  tan,
+	"asin": func(a Angle, v float64) float64 { return a.fromRadians(math.Asin(v)) },
+	"acos": func(a Angle, v float64) float64 { return a.fromRadians(math.Acos(v)) },
+	"atan": func(a Angle, v float64) float64 { return a.fromRadians(math.Atan(v)) },
+}
+
+// tan is the tangent of v, or NaN where the tangent has a pole, such as
+// tan(90) in degrees, which would otherwise come out as a huge number
+// because pi/2 is not exact.
+func tan(a Angle, v float64) float64 {
+	r := a.toRadians(v)
+	if clean(math.Cos(r)) == 0 {
+		return math.NaN()
+	}
+	return math.Tan(r)
+}
+
+// name evaluates the variable or the call of the function named by t.
+func (p *parser) name(t token) (float64, error) {
+	if v, ok := p.env.Vars[t.text]; ok {
+		return v, nil
+	}
+	if d, ok := p.env.Funcs[t.text]; ok {
+		return p.call(t, d)
+	}
+	f, ok := functions[t.text]
+	if !ok {
+		return 0, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unknown name %q", t.text)}
+	}
+	if _, ok := p.accept("("); !ok {
+		return 0, &SyntaxError{Pos: p.peek().pos, Msg: fmt.Sprintf("missing '(' after %s", t.text)}
+	}
+	arg, err := p.parenthesized()
+	if err != nil {
+		return 0, err
+	}
+	v := f(p.env.Angle, arg)
+	if math.IsNaN(v) {
+		return 0, ErrDomain
+	}
+	return clean(v), nil
+}
+
+// clean rounds away the error left by converting angles through pi, so
+// sin(180) in degrees is 0 rather than 1.2e-16 and tan(45) is 1 rather
+// than 0.9999999999999999.
+func clean(v float64) float64 {
+	if math.Abs(v) < 1e-15 {
+		return 0
+	}
+	v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'g', 15, 64), 64)
+	return v
+}
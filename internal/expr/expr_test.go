@@ -0,0 +1,194 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"42", 42},
+		{"-3.5", -3.5},
+		{"(12.5 * 4) - 3", 47},
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 / 4", 2.5},
+		{"8 - 2 - 1", 5},
+		{"16 / 4 / 2", 2},
+		{"6 x 7", 42},
+		{"6×7", 42},
+		{"9 ÷ 3", 3},
+		{"5 − 8", -3},
+		{"--4", 4},
+		{"2 * -3", -6},
+		{"50%", 0.5},
+		{"200 * 15%", 30},
+		{"1.5e3", 1500},
+		{"2e-1", 0.2},
+		{".5 + .25", 0.75},
+		{"((1))", 1},
+		{"  7  ", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Eval(tt.input)
+			if err != nil {
+				t.Fatalf("Eval(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Eval(%q) = %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvalSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		msg   string
+		pos   int
+	}{
+		{"", "no number", 1},
+		{"1 +", "no number", 4},
+		{"(1 + 2", "missing ')'", 7},
+		{"1 + 2)", "extra ')'", 6},
+		{"1 + a", `unknown name "a"`, 5},
+		{"1..2", `bad number "1..2"`, 1},
+		{"sin 30", "missing '(' after sin", 5},
+		{"sin(30", "missing ')'", 7},
+		{"* 2", `unexpected "*"`, 1},
+		{"2 3", `unexpected "3"`, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Eval(tt.input)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Eval(%q) error = %v, expected a *SyntaxError", tt.input, err)
+			}
+			if syntaxErr.Msg != tt.msg || syntaxErr.Pos != tt.pos {
+				t.Errorf("Eval(%q) = %q at %d, expected %q at %d", tt.input, syntaxErr.Msg, syntaxErr.Pos, tt.msg, tt.pos)
+			}
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("Expected error to wrap ErrSyntax")
+			}
+		})
+	}
+}
+
+func TestEvalArithmeticErrors(t *testing.T) {
+	if _, err := Eval("5 / (2 - 2)"); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Expected ErrDivisionByZero, got %v", err)
+	}
+	if _, err := Eval("1e308 * 10"); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	tests := []struct {
+		angle    Angle
+		input    string
+		expected float64
+	}{
+		{Degrees, "sin(30)", 0.5},
+		{Degrees, "sin(180)", 0},
+		{Degrees, "cos(60)", 0.5},
+		{Degrees, "tan(45)", 1},
+		{Degrees, "2 * asin(1)", 180},
+		{Degrees, "acos(0)", 90},
+		{Degrees, "atan(1) + 1", 46},
+		{Radians, "cos(0)", 1},
+		{Radians, "atan(0)", 0},
+		{Gradians, "sin(100)", 1},
+		{Gradians, "acos(-1)", 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.angle.String()+" "+tt.input, func(t *testing.T) {
+			got, err := Env{Angle: tt.angle}.Eval(tt.input)
+			if err != nil {
+				t.Fatalf("Eval(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Eval(%q) = %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	for _, input := range []string{"asin(2)", "tan(90)", "tan(-270)"} {
+		if _, err := Eval(input); !errors.Is(err, ErrDomain) {
+			t.Errorf("Eval(%q): expected ErrDomain, got %v", input, err)
+		}
+	}
+	if _, err := (Env{Angle: Gradians}).Eval("tan(100)"); !errors.Is(err, ErrDomain) {
+		t.Errorf("Expected ErrDomain for tan(100) in gradians, got %v", err)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	env := Env{Vars: map[string]float64{"ans": 42, "rate": 0.2}}
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"ans", 42},
+		{"ans / 2", 21},
+		{"100 * rate", 20},
+		{"-ans + 2", -40},
+		{"(ans)%", 0.42},
+	}
+	for _, tt := range tests {
+		got, err := env.Eval(tt.input)
+		if err != nil {
+			t.Fatalf("Eval(%q) returned error: %v", tt.input, err)
+		}
+		if got != tt.expected {
+			t.Errorf("Eval(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+
+	var syntaxErr *SyntaxError
+	if _, err := Eval("ans + 1"); !errors.As(err, &syntaxErr) || syntaxErr.Msg != `unknown name "ans"` {
+		t.Errorf("Expected an unknown name error without variables, got %v", err)
+	}
+}
+
+func TestParseAngle(t *testing.T) {
+	for _, name := range angleNames {
+		a, err := ParseAngle(name)
+		if err != nil || a.String() != name {
+			t.Errorf("ParseAngle(%q) = %v, %v", name, a, err)
+		}
+	}
+	if _, err := ParseAngle("turns"); err == nil {
+		t.Error("Expected an error for an unknown angle unit")
+	}
+}
+
+func TestIsNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"12.5", true},
+		{"-3", true},
+		{" 7 ", true},
+		{"1e3", true},
+		{"1 + 2", false},
+		{"(3)", false},
+		{"Inf", false},
+		{"0x10", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNumber(tt.input); got != tt.expected {
+			t.Errorf("IsNumber(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
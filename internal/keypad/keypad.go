@@ -0,0 +1,245 @@
+// Package keypad describes calculator button layouts: which keys there
+// are, where they sit, what they do and how they are drawn. Layouts can be
+// loaded from TOML files so teams can define their own keypads.
+package keypad
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/tomlfile"
+)
+
+// Class selects the style a key is drawn with.
+type Class string
+
+const (
+	ClassNumber   Class = "number"
+	ClassZero     Class = "zero"
+	ClassOperator Class = "operator"
+	ClassFunction Class = "function"
+	ClassClear    Class = "clear"
+	ClassEquals   Class = "equals"
+)
+
+var classes = map[Class]bool{
+	ClassNumber: true, ClassZero: true, ClassOperator: true,
+	ClassFunction: true, ClassClear: true, ClassEquals: true,
+}
+
+// Actions maps action ids to the calculator buttons they press.
+var Actions = map[string]string{
+	"digit-0": "0", "digit-1": "1", "digit-2": "2", "digit-3": "3", "digit-4": "4",
+	"digit-5": "5", "digit-6": "6", "digit-7": "7", "digit-8": "8", "digit-9": "9",
+	"decimal":     ".",
+	"add":         "+",
+	"subtract":    "-",
+	"multiply":    "x",
+	"divide":      "/",
+	"percent":     "%",
+	"negate":      "+/-",
+	"equals":      "=",
+	"clear":       "AC",
+	"clear-entry": "CE",
+}
+
+// Key is one button on the keypad.
+type Key struct {
+	Label  string `toml:"label"`
+	Action string `toml:"action"`
+	Span   int    `toml:"span"` // button positions covered, 1 when omitted or 0
+	Class  Class  `toml:"class"`
+}
+
+// Button returns the calculator button the key presses.
+func (k Key) Button() string {
+	return Actions[k.Action]
+}
+
+// Row is a horizontal line of keys.
+type Row struct {
+	Keys []Key `toml:"keys"`
+}
+
+// Layout is a keypad: its rows of keys from top to bottom.
+type Layout struct {
+	Name string `toml:"name"`
+	Rows []Row  `toml:"row"`
+}
+
+// Key returns the key at column x of row y.
+func (l Layout) Key(x, y int) Key {
+	return l.Rows[y].Keys[x]
+}
+
+// Columns returns the width of the widest row in button positions.
+func (l Layout) Columns() int {
+	columns := 0
+	for _, row := range l.Rows {
+		n := 0
+		for _, k := range row.Keys {
+			n += k.Span
+		}
+		columns = max(columns, n)
+	}
+	return columns
+}
+
+// Column returns the grid column where key x of row y starts.
+func (l Layout) Column(x, y int) int {
+	col := 0
+	for _, k := range l.Rows[y].Keys[:x] {
+		col += k.Span
+	}
+	return col
+}
+
+// KeyAt returns the index of the key in row y that covers grid column col,
+// or of the last key when the row ends before col.
+func (l Layout) KeyAt(col, y int) int {
+	start := 0
+	for x, k := range l.Rows[y].Keys {
+		if col < start+k.Span {
+			return x
+		}
+		start += k.Span
+	}
+	return len(l.Rows[y].Keys) - 1
+}
+
+// Find returns the position of the first key that presses button.
+func (l Layout) Find(button string) (int, int, bool) {
+	for y, row := range l.Rows {
+		for x, k := range row.Keys {
+			if k.Button() == button {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func key(label, action string, class Class) Key {
+	return Key{Label: label, Action: action, Span: 1, Class: class}
+}
+
+// Default returns the classic Casio-style keypad.
+func Default() Layout {
+	zero := key("0", "digit-0", ClassZero)
+	zero.Span = 2
+	return Layout{
+		Name: "classic",
+		Rows: []Row{
+			{Keys: []Key{key("AC", "clear", ClassClear), key("+/-", "negate", ClassFunction), key("%", "percent", ClassFunction), key("/", "divide", ClassOperator)}},
+			{Keys: []Key{key("7", "digit-7", ClassNumber), key("8", "digit-8", ClassNumber), key("9", "digit-9", ClassNumber), key("x", "multiply", ClassOperator)}},
+			{Keys: []Key{key("4", "digit-4", ClassNumber), key("5", "digit-5", ClassNumber), key("6", "digit-6", ClassNumber), key("-", "subtract", ClassOperator)}},
+			{Keys: []Key{key("1", "digit-1", ClassNumber), key("2", "digit-2", ClassNumber), key("3", "digit-3", ClassNumber), key("+", "add", ClassOperator)}},
+			{Keys: []Key{zero, key(".", "decimal", ClassFunction), key("=", "equals", ClassEquals)}},
+		},
+	}
+}
+
+// Load reads a layout from a TOML file, fills in defaults and validates
+// it. Every problem found is reported, each with its row and key.
+func Load(path string) (Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Layout{}, err
+	}
+	l, err := Parse(string(data))
+	if err != nil {
+		return Layout{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Parse decodes a TOML layout description, fills in defaults and
+// validates it.
+func Parse(data string) (Layout, error) {
+	var l Layout
+	if err := tomlfile.Decode(data, &l); err != nil {
+		return Layout{}, err
+	}
+	l.applyDefaults()
+	if err := l.Validate(); err != nil {
+		return Layout{}, err
+	}
+	return l, nil
+}
+
+// applyDefaults fills in what a layout file may leave out: a span of 1, the
+// label of the pressed button and a class matching the action.
+func (l *Layout) applyDefaults() {
+	for y := range l.Rows {
+		for x := range l.Rows[y].Keys {
+			k := &l.Rows[y].Keys[x]
+			if k.Span == 0 {
+				k.Span = 1
+			}
+			if k.Label == "" {
+				k.Label = k.Button()
+			}
+			if k.Class == "" {
+				k.Class = defaultClass(k.Action)
+			}
+		}
+	}
+}
+
+func defaultClass(action string) Class {
+	switch action {
+	case "digit-0":
+		return ClassZero
+	case "add", "subtract", "multiply", "divide":
+		return ClassOperator
+	case "equals":
+		return ClassEquals
+	case "clear", "clear-entry":
+		return ClassClear
+	}
+	if strings.HasPrefix(action, "digit-") {
+		return ClassNumber
+	}
+	return ClassFunction
+}
+
+// Validate reports every problem with the layout.
+func (l Layout) Validate() error {
+	var errs []error
+	if len(l.Rows) == 0 {
+		errs = append(errs, errors.New("layout has no rows"))
+	}
+	for y, row := range l.Rows {
+		if len(row.Keys) == 0 {
+			errs = append(errs, fmt.Errorf("row %d: no keys", y+1))
+		}
+		for x, k := range row.Keys {
+			at := fmt.Sprintf("row %d, key %d", y+1, x+1)
+			if _, ok := Actions[k.Action]; !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown action %q (known: %s)", at, k.Action, strings.Join(actionIDs(), ", ")))
+			}
+			if k.Label == "" {
+				errs = append(errs, fmt.Errorf("%s: empty label", at))
+			}
+			if k.Span < 1 {
+				errs = append(errs, fmt.Errorf("%s: span must be at least 1, got %d", at, k.Span))
+			}
+			if !classes[k.Class] {
+				errs = append(errs, fmt.Errorf("%s: unknown class %q", at, k.Class))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func actionIDs() []string {
+	ids := make([]string, 0, len(Actions))
+	for id := range Actions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,168 @@
+package keypad
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLayout(t *testing.T) {
+	l := Default()
+	if err := l.Validate(); err != nil {
+		t.Fatalf("Default layout is invalid: %v", err)
+	}
+	if l.Columns() != 4 {
+		t.Errorf("Expected 4 columns, got %d", l.Columns())
+	}
+
+	var labels []string
+	for _, row := range l.Rows {
+		for _, k := range row.Keys {
+			labels = append(labels, k.Label)
+			if k.Button() == "" {
+				t.Errorf("Key %q has no button", k.Label)
+			}
+		}
+	}
+	expected := "AC +/- % / 7 8 9 x 4 5 6 - 1 2 3 + 0 . ="
+	if got := strings.Join(labels, " "); got != expected {
+		t.Errorf("Expected keys %q, got %q", expected, got)
+	}
+
+	x, y, ok := l.Find("=")
+	if !ok || x != 2 || y != 4 {
+		t.Errorf("Expected = at (2, 4), got (%d, %d)", x, y)
+	}
+}
+
+const accountingLayout = `
+name = "accounting"
+
+[[row]]
+keys = [
+  { label = "C", action = "clear" },
+  { label = "÷", action = "divide" },
+  { label = "×", action = "multiply" },
+]
+
+[[row]]
+keys = [
+  { action = "digit-0", span = 2 },
+  { action = "digit-0", label = "00", class = "number" },
+  { action = "equals" },
+]
+`
+
+func TestParseAppliesDefaults(t *testing.T) {
+	l, err := Parse(accountingLayout)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if l.Name != "accounting" || len(l.Rows) != 2 {
+		t.Fatalf("Unexpected layout %+v", l)
+	}
+
+	tests := []struct {
+		x, y   int
+		expect Key
+	}{
+		{0, 0, Key{Label: "C", Action: "clear", Span: 1, Class: ClassClear}},
+		{1, 0, Key{Label: "÷", Action: "divide", Span: 1, Class: ClassOperator}},
+		{0, 1, Key{Label: "0", Action: "digit-0", Span: 2, Class: ClassZero}},
+		{1, 1, Key{Label: "00", Action: "digit-0", Span: 1, Class: ClassNumber}},
+		{2, 1, Key{Label: "=", Action: "equals", Span: 1, Class: ClassEquals}},
+	}
+	for _, tt := range tests {
+		if got := l.Key(tt.x, tt.y); got != tt.expect {
+			t.Errorf("Key(%d, %d) = %+v, expected %+v", tt.x, tt.y, got, tt.expect)
+		}
+	}
+	if l.Columns() != 4 {
+		t.Errorf("Expected 4 columns, got %d", l.Columns())
+	}
+}
+
+func TestValidationReportsEveryProblem(t *testing.T) {
+	_, err := Parse(`
+[[row]]
+keys = [
+  { label = "?", action = "square-root" },
+  { label = "7", action = "digit-7", span = -1 },
+  { label = "8", action = "digit-8", class = "sparkly" },
+]
+
+[[row]]
+keys = []
+`)
+	if err == nil {
+		t.Fatalf("Expected validation errors")
+	}
+	for _, want := range []string{
+		`row 1, key 1: unknown action "square-root"`,
+		"row 1, key 2: span must be at least 1, got -1",
+		`row 1, key 3: unknown class "sparkly"`,
+		"row 2: no keys",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in error:\n%v", want, err)
+		}
+	}
+}
+
+func TestParseRejectsEmptyLayout(t *testing.T) {
+	if _, err := Parse(`name = "empty"`); err == nil || !strings.Contains(err.Error(), "no rows") {
+		t.Errorf("Expected no rows error, got %v", err)
+	}
+}
+
+func TestParseRejectsUnknownFields(t *testing.T) {
+	_, err := Parse(`
+[[row]]
+keys = [{ label = "1", action = "digit-1", colour = "red" }]
+`)
+	if err == nil || !strings.Contains(err.Error(), "colour") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layout.toml")
+	if err := os.WriteFile(path, []byte(accountingLayout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if l.Name != "accounting" {
+		t.Errorf("Expected accounting layout, got %q", l.Name)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.toml")
+	if err := os.WriteFile(bad, []byte("[[row]]\nkeys = [{ action = \"nope\" }]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(bad); err == nil || !strings.HasPrefix(err.Error(), bad+":") {
+		t.Errorf("Expected error prefixed with the file name, got %v", err)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Errorf("Expected error for a missing file")
+	}
+}
+
+func TestColumns(t *testing.T) {
+	l := Default()
+	if got := l.Column(2, 4); got != 3 {
+		t.Errorf("Expected = to start at column 3, got %d", got)
+	}
+	for col, expected := range []int{0, 0, 1, 2} {
+		if got := l.KeyAt(col, 4); got != expected {
+			t.Errorf("Column %d of the bottom row: expected key %d, got %d", col, expected, got)
+		}
+	}
+	if got := l.KeyAt(7, 4); got != 2 {
+		t.Errorf("Expected the last key past the end of the row, got %d", got)
+	}
+}
@@ -0,0 +1,228 @@
+// Package numfmt turns calculator results into the strings shown on the
+// display: rounded to a number of decimal places, and grouped in
+// thousands or written in scientific notation.
+package numfmt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Auto is the Decimals setting that shows as many decimal places as a
+// result needs.
+const Auto = -1
+
+// MaxDecimals is the largest number of decimal places a result can be
+// rounded to.
+const MaxDecimals = 15
+
+// Rounding is how a result is rounded to its decimal places.
+type Rounding int
+
+const (
+	HalfUp   Rounding = iota // 2.5 → 3, -2.5 → -3
+	HalfEven                 // 2.5 → 2, 3.5 → 4, the banker's rounding
+	Down                     // towards zero, 2.9 → 2
+	Up                       // away from zero, 2.1 → 3
+)
+
+var roundingNames = []string{"half-up", "half-even", "down", "up"}
+
+func (r Rounding) String() string { return roundingNames[r] }
+
+// Roundings returns every rounding.
+func Roundings() []Rounding {
+	return []Rounding{HalfUp, HalfEven, Down, Up}
+}
+
+// ParseRounding returns the rounding called name.
+func ParseRounding(name string) (Rounding, error) {
+	for i, n := range roundingNames {
+		if n == name {
+			return Rounding(i), nil
+		}
+	}
+	return HalfUp, fmt.Errorf("unknown rounding %q, use %s", name, strings.Join(roundingNames, ", "))
+}
+
+// Notation is how a number is written.
+type Notation int
+
+const (
+	Plain      Notation = iota // 1234567.5
+	Grouped                    // 1,234,567.5
+	Scientific                 // 1.2345675e+06
+)
+
+var notationNames = []string{"plain", "grouped", "scientific"}
+
+func (n Notation) String() string { return notationNames[n] }
+
+// Notations returns every notation.
+func Notations() []Notation {
+	return []Notation{Plain, Grouped, Scientific}
+}
+
+// ParseNotation returns the notation called name.
+func ParseNotation(name string) (Notation, error) {
+	for i, n := range notationNames {
+		if n == name {
+			return Notation(i), nil
+		}
+	}
+	return Plain, fmt.Errorf("unknown number format %q, use %s", name, strings.Join(notationNames, ", "))
+}
+
+// Format describes how results are shown.
+type Format struct {
+	Decimals int // at most this many decimal places, or Auto
+	Rounding Rounding
+	Notation Notation
+}
+
+// Default shows results with as many decimals as they need, without
+// grouping.
+func Default() Format {
+	return Format{Decimals: Auto}
+}
+
+// Result returns v as the calculator keeps it on the display: rounded to
+// the decimal places of f, without trailing zeros. Scientific notation is
+// applied here, since it changes the digits; grouping is left to Display.
+// With automatic decimal places, plain notation keeps the shortest form,
+// which turns to an exponent for large and small values, while grouped
+// notation writes the digits out so there are digits to group.
+//
+// The shortest decimal form of v is rounded rather than its binary value,
+// so 1.005 rounds half up to 1.01 as written.
+func (f Format) Result(v float64) string {
+	switch {
+	case math.IsInf(v, 0) || math.IsNaN(v):
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case f.Notation == Scientific:
+		return f.scientific(v)
+	case math.Abs(v) >= 1e21:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case f.Decimals == Auto && f.Notation == Grouped:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case f.Decimals == Auto:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return f.roundDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// scientific writes v as a mantissa between 1 and 10 and an exponent,
+// rounding the mantissa to the decimal places of f.
+func (f Format) scientific(v float64) string {
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(v, 'e', -1, 64), "e")
+	n, _ := strconv.Atoi(exp)
+	if f.Decimals != Auto {
+		mantissa = f.roundDecimal(mantissa)
+		if strings.TrimPrefix(mantissa, "-") == "10" {
+			mantissa = strings.Replace(mantissa, "10", "1", 1)
+			n++
+		}
+	}
+	return fmt.Sprintf("%se%+03d", mantissa, n)
+}
+
+// roundDecimal rounds the plain decimal number s to the decimal places of
+// f and drops trailing zeros.
+func (f Format) roundDecimal(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > f.Decimals {
+		kept, dropped := whole+frac[:f.Decimals], frac[f.Decimals:]
+		if roundsAway(kept, dropped, f.Rounding) {
+			kept = increment(kept)
+		}
+		whole, frac = kept[:len(kept)-f.Decimals], kept[len(kept)-f.Decimals:]
+	}
+	frac = strings.TrimRight(frac, "0")
+	if strings.Trim(whole+frac, "0") == "" {
+		return "0" // no negative zero
+	}
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// roundsAway reports whether the digits kept go up by one when the digits
+// dropped are rounded off with r.
+func roundsAway(kept, dropped string, r Rounding) bool {
+	rest := strings.Trim(dropped[1:], "0") != ""
+	switch r {
+	case HalfUp:
+		return dropped[0] >= '5'
+	case HalfEven:
+		if dropped[0] != '5' || rest {
+			return dropped[0] >= '5'
+		}
+		return (kept[len(kept)-1]-'0')%2 == 1
+	case Up:
+		return dropped[0] != '0' || rest
+	}
+	return false
+}
+
+// increment adds one to the last digit of the digit string s.
+func increment(s string) string {
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '9' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b)
+}
+
+// Display returns a value from the display as it is shown: grouped in
+// thousands with the Grouped notation. Anything that is not a plain
+// number, such as "Error" or a value in scientific notation, is returned
+// unchanged.
+func (f Format) Display(s string) string {
+	if f.Notation != Grouped {
+		return s
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign = "-"
+	}
+	whole, frac, hasDot := strings.Cut(s[len(sign):], ".")
+	if whole == "" || strings.Trim(whole, "0123456789") != "" || strings.Trim(frac, "0123456789") != "" {
+		return s
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	if hasDot {
+		b.WriteString("." + frac)
+	}
+	return b.String()
+}
+
+// Line applies Display to every space separated word of s, such as the
+// numbers of "1234 + 1 = 1235".
+func (f Format) Line(s string) string {
+	if f.Notation != Grouped {
+		return s
+	}
+	words := strings.Split(s, " ")
+	for i, w := range words {
+		words[i] = f.Display(w)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,95 @@
+package numfmt
+
+import "testing"
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		value    float64
+		expected string
+	}{
+		{"auto", Default(), 1.0 / 3, "0.3333333333333333"},
+		{"auto integer", Default(), 4, "4"},
+		{"auto million", Default(), 1e6, "1e+06"},
+		{"auto large", Default(), 1e21, "1e+21"},
+		{"auto small", Default(), 1e-7, "1e-07"},
+		{"grouped million", Format{Decimals: Auto, Notation: Grouped}, 1e6, "1000000"},
+		{"grouped small", Format{Decimals: Auto, Notation: Grouped}, 1e-7, "0.0000001"},
+		{"grouped large", Format{Decimals: Auto, Notation: Grouped}, 1e21, "1e+21"},
+		{"two places", Format{Decimals: 2}, 1.0 / 3, "0.33"},
+		{"trailing zeros dropped", Format{Decimals: 2}, 2.5, "2.5"},
+		{"no places", Format{Decimals: 0}, 2.5, "3"},
+		{"as written", Format{Decimals: 2}, 1.005, "1.01"},
+		{"carry", Format{Decimals: 2}, 9.999, "10"},
+		{"half-up negative", Format{Decimals: 0}, -2.5, "-3"},
+		{"half-even down", Format{Decimals: 0, Rounding: HalfEven}, 2.5, "2"},
+		{"half-even up", Format{Decimals: 0, Rounding: HalfEven}, 3.5, "4"},
+		{"half-even above tie", Format{Decimals: 0, Rounding: HalfEven}, 2.51, "3"},
+		{"down", Format{Decimals: 1, Rounding: Down}, 2.99, "2.9"},
+		{"down negative", Format{Decimals: 1, Rounding: Down}, -2.99, "-2.9"},
+		{"up", Format{Decimals: 1, Rounding: Up}, 2.01, "2.1"},
+		{"up exact", Format{Decimals: 1, Rounding: Up}, 2.1, "2.1"},
+		{"negative zero", Format{Decimals: 1}, -0.01, "0"},
+		{"small rounded", Format{Decimals: 2}, 1e-7, "0"},
+		{"scientific", Format{Decimals: Auto, Notation: Scientific}, 1234567.5, "1.2345675e+06"},
+		{"scientific rounded", Format{Decimals: 2, Notation: Scientific}, 1234567.5, "1.23e+06"},
+		{"scientific carry", Format{Decimals: 1, Notation: Scientific}, 99.99, "1e+02"},
+		{"scientific small", Format{Decimals: 3, Notation: Scientific}, -0.00012345, "-1.235e-04"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.Result(tt.value); got != tt.expected {
+				t.Errorf("Result(%v): expected %q, got %q", tt.value, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	grouped := Format{Decimals: Auto, Notation: Grouped}
+	tests := []struct {
+		value, expected string
+	}{
+		{"0", "0"},
+		{"123", "123"},
+		{"1234", "1,234"},
+		{"-1234567.125", "-1,234,567.125"},
+		{"1234.", "1,234."},
+		{"Error", "Error"},
+		{"1e+21", "1e+21"},
+		{"-", "-"},
+	}
+	for _, tt := range tests {
+		if got := grouped.Display(tt.value); got != tt.expected {
+			t.Errorf("Display(%q): expected %q, got %q", tt.value, tt.expected, got)
+		}
+	}
+	if got := Default().Display("1234"); got != "1234" {
+		t.Errorf("Plain notation should not group, got %q", got)
+	}
+	if got := grouped.Line("1234 + 1 = 1235"); got != "1,234 + 1 = 1,235" {
+		t.Errorf("Line: got %q", got)
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	for _, name := range roundingNames {
+		r, err := ParseRounding(name)
+		if err != nil || r.String() != name {
+			t.Errorf("ParseRounding(%q) = %v, %v", name, r, err)
+		}
+	}
+	for _, name := range notationNames {
+		n, err := ParseNotation(name)
+		if err != nil || n.String() != name {
+			t.Errorf("ParseNotation(%q) = %v, %v", name, n, err)
+		}
+	}
+	if _, err := ParseRounding("nearest"); err == nil {
+		t.Error("Expected an error for an unknown rounding")
+	}
+	if _, err := ParseNotation("roman"); err == nil {
+		t.Error("Expected an error for an unknown number format")
+	}
+}
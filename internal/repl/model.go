@@ -0,0 +1,109 @@
+package repl
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keys of the interactive prompt, besides the line editing keys of the
+// text input.
+var keys = struct {
+	Submit, Previous, Next, Quit, EOF key.Binding
+}{
+	Submit:   key.NewBinding(key.WithKeys("enter")),
+	Previous: key.NewBinding(key.WithKeys("up", "ctrl+p")),
+	Next:     key.NewBinding(key.WithKeys("down", "ctrl+n")),
+	Quit:     key.NewBinding(key.WithKeys("ctrl+c", "esc")),
+	EOF:      key.NewBinding(key.WithKeys("ctrl+d")),
+}
+
+// model is the interactive prompt: a line editor whose up and down keys
+// step through the history. Lines and their results are printed above the
+// prompt, so they stay in the terminal's scrollback.
+type model struct {
+	session *Session
+	input   textinput.Model
+	// recall is the history entry shown in the input, len(history) for
+	// the line being typed
+	recall int
+	// draft keeps the line being typed while browsing the history
+	draft string
+}
+
+// NewModel returns the interactive prompt for s.
+func NewModel(s *Session) tea.Model {
+	input := textinput.New()
+	input.Prompt = Prompt
+	input.Focus()
+	return model{session: s, input: input, recall: len(s.History())}
+}
+
+func (m model) Init() tea.Cmd { return textinput.Blink }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.EOF) && m.input.Value() == "":
+			return m, tea.Quit
+		case key.Matches(msg, keys.Submit):
+			return m.submit()
+		case key.Matches(msg, keys.Previous):
+			return m.browse(-1), nil
+		case key.Matches(msg, keys.Next):
+			return m.browse(1), nil
+		}
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// submit runs the line in the input and prints it with its result.
+func (m model) submit() (tea.Model, tea.Cmd) {
+	line := m.input.Value()
+	out, err := m.session.Execute(line)
+	if errors.Is(err, ErrQuit) {
+		return m, tea.Quit
+	}
+	m.input.Reset()
+	m.recall, m.draft = len(m.session.History()), ""
+
+	printed := Prompt + line
+	switch {
+	case err != nil:
+		printed += "\nError: " + err.Error()
+	case out != "":
+		printed += "\n" + out
+	}
+	return m, tea.Println(printed)
+}
+
+// browse moves delta entries through the history, back to the line being
+// typed after the newest entry.
+func (m model) browse(delta int) model {
+	history := m.session.History()
+	next := min(max(m.recall+delta, 0), len(history))
+	if next == m.recall {
+		return m
+	}
+	if m.recall == len(history) {
+		m.draft = m.input.Value()
+	}
+	m.recall = next
+	if next == len(history) {
+		m.input.SetValue(m.draft)
+	} else {
+		m.input.SetValue(history[next])
+	}
+	m.input.CursorEnd()
+	return m
+}
+
+func (m model) View() string {
+	return m.input.View()
+}
@@ -0,0 +1,57 @@
+package repl
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeLine(t *testing.T, m tea.Model, line string) (tea.Model, tea.Cmd) {
+	t.Helper()
+	for _, r := range line {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestModelSubmit(t *testing.T) {
+	m, cmd := typeLine(t, NewModel(newTestSession()), "6*7")
+	if cmd == nil {
+		t.Fatal("Expected the line and its result to be printed")
+	}
+	if got := m.(model).input.Value(); got != "" {
+		t.Errorf("Expected the input to be cleared, got %q", got)
+	}
+
+	_, cmd = typeLine(t, m, "quit")
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected quit to end the session")
+	}
+}
+
+func TestModelHistory(t *testing.T) {
+	var m tea.Model = NewModel(newTestSession())
+	m, _ = typeLine(t, m, "1+1")
+	m, _ = typeLine(t, m, "2+2")
+	for _, r := range "3+" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	steps := []struct {
+		key      tea.KeyType
+		expected string
+	}{
+		{tea.KeyUp, "2+2"},
+		{tea.KeyUp, "1+1"},
+		{tea.KeyUp, "1+1"},
+		{tea.KeyDown, "2+2"},
+		{tea.KeyDown, "3+"},
+		{tea.KeyDown, "3+"},
+	}
+	for i, step := range steps {
+		m, _ = m.Update(tea.KeyMsg{Type: step.key})
+		if got := m.(model).input.Value(); got != step.expected {
+			t.Errorf("Step %d: expected %q, got %q", i+1, step.expected, got)
+		}
+	}
+}
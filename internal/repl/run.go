@@ -0,0 +1,57 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Prompt is shown before each line a user types.
+const Prompt = "> "
+
+// RunLines evaluates each line of r as piped input: results are written
+// to w, one line each, and errors to errw with the number of the line
+// that caused them. It reports whether every line succeeded.
+func RunLines(s *Session, r io.Reader, w, errw io.Writer) (bool, error) {
+	ok := true
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		out, err := s.Execute(scanner.Text())
+		if errors.Is(err, ErrQuit) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(errw, "line %d: %v\n", n, err)
+			ok = false
+			continue
+		}
+		if out != "" {
+			fmt.Fprintln(w, out)
+		}
+	}
+	return ok, scanner.Err()
+}
+
+// RunPrompt runs a session on a terminal without cursor control, such as
+// a dumb terminal: it prints the prompt and reads a line at a time,
+// relying on the terminal for line editing.
+func RunPrompt(s *Session, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprint(w, Prompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return scanner.Err()
+		}
+		out, err := s.Execute(scanner.Text())
+		switch {
+		case errors.Is(err, ErrQuit):
+			return nil
+		case err != nil:
+			fmt.Fprintf(w, "Error: %v\n", err)
+		case out != "":
+			fmt.Fprintln(w, out)
+		}
+	}
+}
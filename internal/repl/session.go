@@ -0,0 +1,217 @@
+// Package repl evaluates expressions a line at a time, for the repl
+// subcommand and for input piped into the calculator. The result of each
+// line is kept as ans for the next one.
+package repl
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+// ErrQuit is returned by Execute for the quit and exit commands.
+var ErrQuit = errors.New("quit")
+
+// helpText lists the commands of a session.
+const helpText = `Enter an expression such as (12.5 * 4) - 3 or 2 * sin(30).
+  ans       the last result
+  A = 42    set a variable
+  f(x) = x * 1.2
+            define a function, called as f(100)
+  sto A     store the last result in A
+  rcl A     recall A as the last result
+  del A     delete a variable or function
+  vars      list the variables and functions
+  history   list the expressions entered
+  !!        evaluate the last expression again
+  !n        evaluate expression n of the history again
+  help      show this help
+  quit      leave (or exit, or ctrl+d)`
+
+// Session evaluates lines one after another.
+type Session struct {
+	env     expr.Env
+	format  numfmt.Format
+	ans     float64
+	history []string
+	// save, when set, keeps the variables and functions after a change
+	save func(env expr.Env) error
+}
+
+// NewSession returns a session evaluating in env, with results formatted
+// by f. ans starts at 0.
+func NewSession(env expr.Env, f numfmt.Format) *Session {
+	return &Session{env: env, format: f}
+}
+
+// SaveWith calls save with the session's environment whenever a variable
+// or function is set or deleted.
+func (s *Session) SaveWith(save func(env expr.Env) error) {
+	s.save = save
+}
+
+// History returns the expressions entered, oldest first.
+func (s *Session) History() []string {
+	return s.history
+}
+
+// Eval evaluates expression and returns the result as the LCD shows it.
+// The result, as rounded for the display, becomes ans.
+func (s *Session) Eval(expression string) (string, error) {
+	v, err := s.withAns().Eval(expression)
+	if err != nil {
+		return "", err
+	}
+	result := s.format.Result(v)
+	s.ans, _ = strconv.ParseFloat(result, 64)
+	return s.format.Display(result), nil
+}
+
+// withAns returns the session's environment with ans set.
+func (s *Session) withAns() expr.Env {
+	env := s.env
+	env.Vars = maps.Clone(env.Vars)
+	if env.Vars == nil {
+		env.Vars = map[string]float64{}
+	}
+	env.Vars["ans"] = s.ans
+	return env
+}
+
+// Execute runs a line: a command, or an expression that is evaluated and
+// added to the history. It returns what to print, which for a recalled
+// expression starts with the expression. Blank lines and lines starting
+// with # do nothing.
+func (s *Session) Execute(line string) (string, error) {
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "" || strings.HasPrefix(line, "#"):
+		return "", nil
+	case line == "quit" || line == "exit":
+		return "", ErrQuit
+	case line == "help":
+		return helpText, nil
+	case line == "history":
+		return s.listHistory(), nil
+	case line == "vars":
+		return s.listDefinitions(), nil
+	case hasCommand(line, "sto"), hasCommand(line, "rcl"), hasCommand(line, "del"):
+		return s.memory(line[:3], strings.TrimSpace(line[3:]))
+	case strings.HasPrefix(line, "!"):
+		recalled, err := s.recall(line)
+		if err != nil {
+			return "", err
+		}
+		result, err := s.evalAndRecord(recalled)
+		return recalled + "\n" + result, err
+	}
+	if d, ok, err := expr.ParseDefinition(line); ok {
+		if err != nil {
+			return "", err
+		}
+		return s.define(d)
+	}
+	// A trailing =, as pressed on the keypad, asks for the result
+	return s.evalAndRecord(strings.TrimSpace(strings.TrimSuffix(line, "=")))
+}
+
+// hasCommand reports whether line is the command cmd followed by a name.
+func hasCommand(line, cmd string) bool {
+	return strings.HasPrefix(line, cmd+" ")
+}
+
+func (s *Session) evalAndRecord(expression string) (string, error) {
+	s.history = append(s.history, expression)
+	return s.Eval(expression)
+}
+
+// recall returns the expression a !! or !n line refers to.
+func (s *Session) recall(line string) (string, error) {
+	if len(s.history) == 0 {
+		return "", errors.New("no history yet")
+	}
+	if line == "!!" {
+		return s.history[len(s.history)-1], nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(s.history) {
+		return "", fmt.Errorf("no history entry %s, use 1-%d", line[1:], len(s.history))
+	}
+	return s.history[n-1], nil
+}
+
+func (s *Session) listHistory() string {
+	lines := make([]string, len(s.history))
+	for i, h := range s.history {
+		lines[i] = fmt.Sprintf("%4d  %s", i+1, h)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// define adds a variable or function to the session.
+func (s *Session) define(d expr.Definition) (string, error) {
+	env, v, err := s.withAns().Define(d)
+	if err != nil {
+		return "", err
+	}
+	delete(env.Vars, "ans")
+	s.env = env
+	if d.IsFunc() {
+		return d.String(), s.saveDefinitions()
+	}
+	return d.Name + " = " + s.format.Display(s.format.Result(v)), s.saveDefinitions()
+}
+
+// memory runs the sto, rcl and del commands on the variable name.
+func (s *Session) memory(cmd, name string) (string, error) {
+	switch cmd {
+	case "sto":
+		return s.define(expr.Definition{Name: name, Body: strconv.FormatFloat(s.ans, 'g', -1, 64)})
+	case "rcl":
+		v, ok := s.env.Vars[name]
+		if !ok {
+			return "", fmt.Errorf("no variable %s", name)
+		}
+		return s.Eval(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	_, isVar := s.env.Vars[name]
+	_, isFunc := s.env.Funcs[name]
+	if !isVar && !isFunc {
+		return "", fmt.Errorf("no variable or function %s", name)
+	}
+	s.env.Vars, s.env.Funcs = maps.Clone(s.env.Vars), maps.Clone(s.env.Funcs)
+	delete(s.env.Vars, name)
+	delete(s.env.Funcs, name)
+	return "deleted " + name, s.saveDefinitions()
+}
+
+func (s *Session) saveDefinitions() error {
+	if s.save == nil {
+		return nil
+	}
+	if err := s.save(s.env); err != nil {
+		return fmt.Errorf("saving definitions: %w", err)
+	}
+	return nil
+}
+
+// listDefinitions lists the variables, then the functions, by name.
+func (s *Session) listDefinitions() string {
+	var lines []string
+	for _, name := range slices.Sorted(maps.Keys(s.env.Vars)) {
+		lines = append(lines, name+" = "+s.format.Display(s.format.Result(s.env.Vars[name])))
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.env.Funcs)) {
+		lines = append(lines, s.env.Funcs[name].String())
+	}
+	if len(lines) == 0 {
+		return "no variables or functions"
+	}
+	return strings.Join(lines, "\n")
+}
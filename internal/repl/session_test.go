@@ -0,0 +1,162 @@
+package repl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/expr"
+	"github.com/dmisiuk/goose-tui-calculator/internal/numfmt"
+)
+
+func newTestSession() *Session {
+	return NewSession(expr.Env{}, numfmt.Default())
+}
+
+func TestExecute(t *testing.T) {
+	s := newTestSession()
+	steps := []struct {
+		line     string
+		expected string
+	}{
+		{"ans", "0"},
+		{"(12.5 * 4) - 3", "47"},
+		{"ans * 2", "94"},
+		{"  ", ""},
+		{"# a comment", ""},
+		{"!!", "ans * 2\n188"},
+		{"!2", "(12.5 * 4) - 3\n47"},
+		{"history", "   1  ans\n   2  (12.5 * 4) - 3\n   3  ans * 2\n   4  ans * 2\n   5  (12.5 * 4) - 3"},
+		{"1+2=", "3"},
+		{"cos(0) =", "1"},
+	}
+	for _, step := range steps {
+		got, err := s.Execute(step.line)
+		if err != nil {
+			t.Fatalf("Execute(%q) returned error: %v", step.line, err)
+		}
+		if got != step.expected {
+			t.Errorf("Execute(%q): expected %q, got %q", step.line, step.expected, got)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	s := newTestSession()
+	if _, err := s.Execute("!!"); err == nil || err.Error() != "no history yet" {
+		t.Errorf("Expected an empty history error, got %v", err)
+	}
+	s.Execute("6 * 7")
+	if _, err := s.Execute("1/0"); !errors.Is(err, expr.ErrDivisionByZero) {
+		t.Errorf("Expected division by zero, got %v", err)
+	}
+	if got, _ := s.Execute("ans"); got != "42" {
+		t.Errorf("Expected a failed line to keep ans, got %q", got)
+	}
+	if _, err := s.Execute("!9"); err == nil || !strings.Contains(err.Error(), "no history entry 9, use 1-3") {
+		t.Errorf("Expected a missing history entry error, got %v", err)
+	}
+	for _, line := range []string{"quit", "exit"} {
+		if _, err := s.Execute(line); !errors.Is(err, ErrQuit) {
+			t.Errorf("Expected %s to quit, got %v", line, err)
+		}
+	}
+}
+
+func TestAnsIsRoundedResult(t *testing.T) {
+	s := NewSession(expr.Env{}, numfmt.Format{Decimals: 2, Notation: numfmt.Grouped})
+	if got, _ := s.Execute("2000 / 3"); got != "666.67" {
+		t.Errorf("Expected the rounded result, got %q", got)
+	}
+	if got, _ := s.Execute("ans * 3"); got != "2,000.01" {
+		t.Errorf("Expected ans to be the result as shown, got %q", got)
+	}
+}
+
+func TestRunLines(t *testing.T) {
+	var out, errOut strings.Builder
+	ok, err := RunLines(newTestSession(), strings.NewReader("1+2\nans*10\n1/0\nsin(\nquit\n5\n"), &out, &errOut)
+	if err != nil {
+		t.Fatalf("RunLines returned error: %v", err)
+	}
+	if ok {
+		t.Error("Expected RunLines to report the failed lines")
+	}
+	if out.String() != "3\n30\n" {
+		t.Errorf("Unexpected results %q", out.String())
+	}
+	if errOut.String() != "line 3: division by zero\nline 4: no number at column 5\n" {
+		t.Errorf("Unexpected errors %q", errOut.String())
+	}
+}
+
+func TestRunPrompt(t *testing.T) {
+	var out strings.Builder
+	if err := RunPrompt(newTestSession(), strings.NewReader("2+2\n1/0\n"), &out); err != nil {
+		t.Fatalf("RunPrompt returned error: %v", err)
+	}
+	if expected := "> 4\n> Error: division by zero\n> \n"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	s := newTestSession()
+	var saved []expr.Env
+	s.SaveWith(func(env expr.Env) error {
+		saved = append(saved, env)
+		return nil
+	})
+	steps := []struct {
+		line     string
+		expected string
+	}{
+		{"vars", "no variables or functions"},
+		{"vat(x) = x * 1.2", "vat(x) = x * 1.2"},
+		{"vat(100)", "120"},
+		{"sto A", "A = 120"},
+		{"rate = ans / 4", "rate = 30"},
+		{"1 + 1", "2"},
+		{"rcl A", "120"},
+		{"ans + rate", "150"},
+		{"vars", "A = 120\nrate = 30\nvat(x) = x * 1.2"},
+		{"del rate", "deleted rate"},
+		{"vars", "A = 120\nvat(x) = x * 1.2"},
+	}
+	for _, step := range steps {
+		got, err := s.Execute(step.line)
+		if err != nil {
+			t.Fatalf("Execute(%q) returned error: %v", step.line, err)
+		}
+		if got != step.expected {
+			t.Errorf("Execute(%q): expected %q, got %q", step.line, step.expected, got)
+		}
+	}
+	if len(saved) != 4 {
+		t.Fatalf("expected a save for each change, got %d", len(saved))
+	}
+	if last := saved[3]; last.Vars["A"] != 120 || len(last.Vars) != 1 || len(last.Funcs) != 1 {
+		t.Errorf("unexpected saved definitions %+v", last)
+	}
+}
+
+func TestDefinitionErrors(t *testing.T) {
+	s := newTestSession()
+	for _, line := range []string{"rcl B", "del B", "sin(x) = x", "f(x) = y", "ans = 2"} {
+		if _, err := s.Execute(line); err == nil {
+			t.Errorf("Execute(%q): expected an error", line)
+		}
+	}
+
+	if _, err := s.Execute("cos(0)=1"); err == nil || strings.Contains(err.Error(), "built-in") {
+		t.Errorf("expected cos(0)=1 to be evaluated rather than defined, got %v", err)
+	}
+
+	s.SaveWith(func(expr.Env) error { return errors.New("disk full") })
+	if _, err := s.Execute("B = 1"); err == nil || !strings.Contains(err.Error(), "saving definitions: disk full") {
+		t.Errorf("expected the save error to be reported, got %v", err)
+	}
+	if got, _ := s.Execute("B"); got != "1" {
+		t.Errorf("expected B to be set despite the save error, got %q", got)
+	}
+}
@@ -0,0 +1,44 @@
+package theme
+
+import (
+	"strconv"
+
+	"github.com/muesli/termenv"
+)
+
+// Degrade converts every color of the theme to the nearest one the color
+// profile can show, as ANSI color numbers for 256 and 16 color terminals.
+// True color themes are returned unchanged. Monochrome terminals are left
+// to the caller, which has to show states without color.
+func (t Theme) Degrade(p termenv.Profile) Theme {
+	if p != termenv.ANSI256 && p != termenv.ANSI {
+		return t
+	}
+	for _, c := range t.colors() {
+		*c.value = degrade(*c.value, p)
+	}
+	return t
+}
+
+func degrade(s string, p termenv.Profile) string {
+	switch c := p.Color(s).(type) {
+	case termenv.ANSIColor:
+		return strconv.Itoa(int(c))
+	case termenv.ANSI256Color:
+		return strconv.Itoa(int(c))
+	}
+	return s
+}
+
+// Readable returns fg, or black or white when fg would be drawn in the
+// same color as bg. Nearby colors can collapse into one when a theme is
+// degraded to 16 colors.
+func Readable(fg, bg string) string {
+	if fg != bg {
+		return fg
+	}
+	if l, _, _ := termenv.ConvertToRGB(termenv.TrueColor.Color(bg)).Lab(); l > 0.5 {
+		return "0"
+	}
+	return "15"
+}
@@ -0,0 +1,342 @@
+// Package theme describes the colors the calculator is drawn with. A
+// handful of themes are built in; more can be loaded from TOML or JSON
+// files.
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dmisiuk/goose-tui-calculator/internal/tomlfile"
+)
+
+// LCD colors the display.
+type LCD struct {
+	Background string `toml:"background" json:"background"`
+	Text       string `toml:"text" json:"text"`
+	Dim        string `toml:"dim" json:"dim"` // previous operation line
+}
+
+// Buttons colors the keys, one background per keypad class.
+type Buttons struct {
+	Text     string `toml:"text" json:"text"`
+	Number   string `toml:"number" json:"number"`
+	Zero     string `toml:"zero" json:"zero"`
+	Operator string `toml:"operator" json:"operator"`
+	Function string `toml:"function" json:"function"`
+	Clear    string `toml:"clear" json:"clear"`
+	Equals   string `toml:"equals" json:"equals"`
+}
+
+// Feedback colors a key while it is selected or pressed.
+type Feedback struct {
+	Highlight     string `toml:"highlight" json:"highlight"` // cursor
+	HighlightText string `toml:"highlight_text" json:"highlight_text"`
+	Pressed       string `toml:"pressed" json:"pressed"` // enter or mouse
+	PressedText   string `toml:"pressed_text" json:"pressed_text"`
+	Keyboard      string `toml:"keyboard" json:"keyboard"` // typed directly
+	KeyboardText  string `toml:"keyboard_text" json:"keyboard_text"`
+}
+
+// Theme is a complete set of colors. Colors are hex values such as
+// "#1B5E4F" or ANSI color numbers such as "2".
+type Theme struct {
+	Name     string   `toml:"name" json:"name"`
+	Base     string   `toml:"base" json:"base"` // built-in theme filling in missing colors
+	LCD      LCD      `toml:"lcd" json:"lcd"`
+	Buttons  Buttons  `toml:"buttons" json:"buttons"`
+	Feedback Feedback `toml:"feedback" json:"feedback"`
+	Border   string   `toml:"border" json:"border"` // calculator body and tape pane
+	Logo     string   `toml:"logo" json:"logo"`
+	Help     string   `toml:"help" json:"help"`
+
+	// Path is the file the theme was loaded from, empty for built-in
+	// themes. It tells a theme file apart from a built-in theme of the
+	// same name.
+	Path string `toml:"-" json:"-"`
+}
+
+// Classic returns the Casio-inspired theme the calculator has always had.
+func Classic() Theme {
+	return Theme{
+		Name: "classic",
+		LCD:  LCD{Background: "#1B5E4F", Text: "#D5F5E3", Dim: "#82C9B5"},
+		Buttons: Buttons{
+			Text:     "#FFFFFF",
+			Number:   "#5D6D7E",
+			Zero:     "#34495E",
+			Operator: "#D68910",
+			Function: "#7F8C8D",
+			Clear:    "#C0392B",
+			Equals:   "#E67E22",
+		},
+		Feedback: Feedback{
+			Highlight: "#FFD700", HighlightText: "#000000",
+			Pressed: "#FF4500", PressedText: "#FFFFFF",
+			Keyboard: "#6A5ACD", KeyboardText: "#FFFFFF",
+		},
+		Border: "#95A5A6",
+		Logo:   "#FFFFFF",
+		Help:   "#95A5A6",
+	}
+}
+
+// Builtins returns the bundled themes in cycling order.
+func Builtins() []Theme {
+	return []Theme{
+		Classic(),
+		{
+			Name: "dark",
+			LCD:  LCD{Background: "#101418", Text: "#7CFC9A", Dim: "#3E7C52"},
+			Buttons: Buttons{
+				Text:     "#E6E6E6",
+				Number:   "#2B2F36",
+				Zero:     "#1F2329",
+				Operator: "#3A4F7A",
+				Function: "#3B4048",
+				Clear:    "#7A2E2E",
+				Equals:   "#4C6FB3",
+			},
+			Feedback: Feedback{
+				Highlight: "#E5C07B", HighlightText: "#101418",
+				Pressed: "#C678DD", PressedText: "#101418",
+				Keyboard: "#56B6C2", KeyboardText: "#101418",
+			},
+			Border: "#4B5263",
+			Logo:   "#E6E6E6",
+			Help:   "#5C6370",
+		},
+		{
+			Name: "light",
+			LCD:  LCD{Background: "#C8D4B8", Text: "#1E2A1A", Dim: "#5E6E55"},
+			Buttons: Buttons{
+				Text:     "#1C1C1C",
+				Number:   "#E8E8E8",
+				Zero:     "#D6D6D6",
+				Operator: "#F5C46B",
+				Function: "#CFCFCF",
+				Clear:    "#F08A80",
+				Equals:   "#F0A04B",
+			},
+			Feedback: Feedback{
+				Highlight: "#4A90D9", HighlightText: "#FFFFFF",
+				Pressed: "#D9534F", PressedText: "#FFFFFF",
+				Keyboard: "#7E57C2", KeyboardText: "#FFFFFF",
+			},
+			Border: "#8A8A8A",
+			Logo:   "#3C3C3C",
+			Help:   "#6E6E6E",
+		},
+		{
+			Name: "high-contrast",
+			LCD:  LCD{Background: "#000000", Text: "#FFFFFF", Dim: "#FFFF00"},
+			Buttons: Buttons{
+				Text:     "#FFFFFF",
+				Number:   "#000000",
+				Zero:     "#000000",
+				Operator: "#0000AA",
+				Function: "#333333",
+				Clear:    "#AA0000",
+				Equals:   "#006600",
+			},
+			Feedback: Feedback{
+				Highlight: "#FFFF00", HighlightText: "#000000",
+				Pressed: "#00FFFF", PressedText: "#000000",
+				Keyboard: "#FF00FF", KeyboardText: "#000000",
+			},
+			Border: "#FFFFFF",
+			Logo:   "#FFFFFF",
+			Help:   "#FFFFFF",
+		},
+		{
+			Name: "solarized",
+			LCD:  LCD{Background: "#002B36", Text: "#93A1A1", Dim: "#586E75"},
+			Buttons: Buttons{
+				Text:     "#FDF6E3",
+				Number:   "#073642",
+				Zero:     "#073642",
+				Operator: "#B58900",
+				Function: "#586E75",
+				Clear:    "#DC322F",
+				Equals:   "#CB4B16",
+			},
+			Feedback: Feedback{
+				Highlight: "#2AA198", HighlightText: "#002B36",
+				Pressed: "#D33682", PressedText: "#FDF6E3",
+				Keyboard: "#6C71C4", KeyboardText: "#FDF6E3",
+			},
+			Border: "#657B83",
+			Logo:   "#EEE8D5",
+			Help:   "#839496",
+		},
+	}
+}
+
+// Builtin returns the bundled theme called name.
+func Builtin(name string) (Theme, bool) {
+	for _, t := range Builtins() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// Names returns the names of the bundled themes.
+func Names() []string {
+	var names []string
+	for _, t := range Builtins() {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// Lookup returns the bundled theme called nameOrPath, or loads it from a
+// file when there is no such theme.
+func Lookup(nameOrPath string) (Theme, error) {
+	if t, ok := Builtin(nameOrPath); ok {
+		return t, nil
+	}
+	if _, err := os.Stat(nameOrPath); err != nil {
+		return Theme{}, fmt.Errorf("unknown theme %q (built-in: %s)", nameOrPath, strings.Join(Names(), ", "))
+	}
+	return Load(nameOrPath)
+}
+
+// Load reads a theme from a .toml or .json file. Colors left out are taken
+// from the theme named by base, classic when it is empty.
+func Load(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	var t Theme
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		t, err = ParseTOML(string(data))
+	case ".json":
+		t, err = ParseJSON(data)
+	default:
+		err = fmt.Errorf("unsupported theme format %q, use .toml or .json", ext)
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	t.Path = path
+	return t, nil
+}
+
+// ParseTOML decodes a TOML theme, fills in missing colors from its base
+// and validates it.
+func ParseTOML(data string) (Theme, error) {
+	var t Theme
+	if err := tomlfile.Decode(data, &t); err != nil {
+		return Theme{}, err
+	}
+	return t.complete()
+}
+
+// ParseJSON decodes a JSON theme, fills in missing colors from its base
+// and validates it.
+func ParseJSON(data []byte) (Theme, error) {
+	var t Theme
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return Theme{}, err
+	}
+	return t.complete()
+}
+
+func (t Theme) complete() (Theme, error) {
+	baseName := t.Base
+	if baseName == "" {
+		baseName = "classic"
+	}
+	base, ok := Builtin(baseName)
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown base theme %q (built-in: %s)", baseName, strings.Join(Names(), ", "))
+	}
+	for _, c := range t.colors() {
+		if *c.value == "" {
+			*c.value = *base.color(c.name)
+		}
+	}
+	if err := t.Validate(); err != nil {
+		return Theme{}, err
+	}
+	return t, nil
+}
+
+type color struct {
+	name  string
+	value *string
+}
+
+// colors lists every color of the theme with its field name in theme
+// files.
+func (t *Theme) colors() []color {
+	return []color{
+		{"lcd.background", &t.LCD.Background},
+		{"lcd.text", &t.LCD.Text},
+		{"lcd.dim", &t.LCD.Dim},
+		{"buttons.text", &t.Buttons.Text},
+		{"buttons.number", &t.Buttons.Number},
+		{"buttons.zero", &t.Buttons.Zero},
+		{"buttons.operator", &t.Buttons.Operator},
+		{"buttons.function", &t.Buttons.Function},
+		{"buttons.clear", &t.Buttons.Clear},
+		{"buttons.equals", &t.Buttons.Equals},
+		{"feedback.highlight", &t.Feedback.Highlight},
+		{"feedback.highlight_text", &t.Feedback.HighlightText},
+		{"feedback.pressed", &t.Feedback.Pressed},
+		{"feedback.pressed_text", &t.Feedback.PressedText},
+		{"feedback.keyboard", &t.Feedback.Keyboard},
+		{"feedback.keyboard_text", &t.Feedback.KeyboardText},
+		{"border", &t.Border},
+		{"logo", &t.Logo},
+		{"help", &t.Help},
+	}
+}
+
+func (t *Theme) color(name string) *string {
+	for _, c := range t.colors() {
+		if c.name == name {
+			return c.value
+		}
+	}
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func validColor(s string) bool {
+	if hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Validate reports every color that is missing or malformed.
+func (t Theme) Validate() error {
+	var errs []error
+	for _, c := range t.colors() {
+		switch v := *c.value; {
+		case v == "":
+			errs = append(errs, fmt.Errorf("%s: missing color", c.name))
+		case !validColor(v):
+			errs = append(errs, fmt.Errorf("%s: invalid color %q, use #RRGGBB or an ANSI number 0-255", c.name, v))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestBuiltinsAreValid(t *testing.T) {
+	expected := []string{"classic", "dark", "light", "high-contrast", "solarized"}
+	if got := strings.Join(Names(), " "); got != strings.Join(expected, " ") {
+		t.Errorf("Expected themes %v, got %v", expected, Names())
+	}
+	for _, th := range Builtins() {
+		if err := th.Validate(); err != nil {
+			t.Errorf("Theme %s is invalid: %v", th.Name, err)
+		}
+	}
+}
+
+func TestParseTOMLFillsFromBase(t *testing.T) {
+	th, err := ParseTOML(`
+name = "ocean"
+base = "dark"
+
+[lcd]
+background = "#003366"
+
+[buttons]
+equals = "33"
+`)
+	if err != nil {
+		t.Fatalf("ParseTOML returned error: %v", err)
+	}
+	dark, _ := Builtin("dark")
+	if th.LCD.Background != "#003366" || th.Buttons.Equals != "33" {
+		t.Errorf("Expected overridden colors, got %+v", th)
+	}
+	if th.LCD.Text != dark.LCD.Text || th.Border != dark.Border {
+		t.Errorf("Expected missing colors from the dark theme, got %+v", th)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	th, err := ParseJSON([]byte(`{"name": "mint", "lcd": {"background": "#AAFFCC"}}`))
+	if err != nil {
+		t.Fatalf("ParseJSON returned error: %v", err)
+	}
+	if th.LCD.Background != "#AAFFCC" || th.LCD.Text != Classic().LCD.Text {
+		t.Errorf("Expected colors over classic, got %+v", th)
+	}
+
+	if _, err := ParseJSON([]byte(`{"lcd": {"glow": "#FFFFFF"}}`)); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+}
+
+func TestParseReportsEveryProblem(t *testing.T) {
+	_, err := ParseTOML(`
+border = "grey"
+[feedback]
+pressed = "#12345"
+`)
+	if err == nil {
+		t.Fatalf("Expected validation errors")
+	}
+	for _, want := range []string{`border: invalid color "grey"`, `feedback.pressed: invalid color "#12345"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %q", want, err)
+		}
+	}
+
+	if _, err := ParseTOML(`base = "neon"`); err == nil || !strings.Contains(err.Error(), `unknown base theme "neon"`) {
+		t.Errorf("Expected unknown base error, got %v", err)
+	}
+	if _, err := ParseTOML(`colour = "#FFFFFF"`); err == nil || !strings.Contains(err.Error(), "unknown fields: colour") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if th, err := Lookup("solarized"); err != nil || th.Name != "solarized" {
+		t.Errorf("Expected built-in solarized, got %+v, %v", th, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "paper.toml")
+	if err := os.WriteFile(path, []byte(`base = "light"`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := Lookup(path)
+	if err != nil {
+		t.Fatalf("Lookup returned error: %v", err)
+	}
+	if th.Name != "paper" {
+		t.Errorf("Expected the name from the file name, got %q", th.Name)
+	}
+
+	if _, err := Lookup("neon"); err == nil || !strings.Contains(err.Error(), "built-in: classic") {
+		t.Errorf("Expected unknown theme error listing built-ins, got %v", err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "paper.yaml")
+	os.WriteFile(bad, nil, 0o644)
+	if _, err := Lookup(bad); err == nil || !strings.Contains(err.Error(), "unsupported theme format") {
+		t.Errorf("Expected unsupported format error, got %v", err)
+	}
+}
+
+func TestDegrade(t *testing.T) {
+	classic := Classic()
+	if classic.Degrade(termenv.TrueColor) != classic {
+		t.Errorf("Expected true color to keep the theme unchanged")
+	}
+
+	for _, p := range []termenv.Profile{termenv.ANSI256, termenv.ANSI} {
+		degraded := classic.Degrade(p)
+		if err := degraded.Validate(); err != nil {
+			t.Errorf("Degraded theme is invalid: %v", err)
+		}
+		if strings.HasPrefix(degraded.LCD.Background, "#") {
+			t.Errorf("Expected ANSI color numbers, got %q", degraded.LCD.Background)
+		}
+	}
+	if got := classic.Degrade(termenv.ANSI).Buttons.Clear; got != "1" && got != "9" {
+		t.Errorf("Expected the red AC key to stay red in 16 colors, got %q", got)
+	}
+}
+
+func TestReadable(t *testing.T) {
+	if got := Readable("15", "4"); got != "15" {
+		t.Errorf("Expected distinct colors to stay, got %q", got)
+	}
+	if got := Readable("4", "4"); got != "15" {
+		t.Errorf("Expected white on blue, got %q", got)
+	}
+	if got := Readable("11", "11"); got != "0" {
+		t.Errorf("Expected black on yellow, got %q", got)
+	}
+}
@@ -0,0 +1,27 @@
+// Package tomlfile decodes the calculator's TOML files strictly, so a
+// misspelled setting is reported instead of silently ignored.
+package tomlfile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Decode decodes data into v and reports every field of data that v has
+// no place for.
+func Decode(data string, v any) error {
+	md, err := toml.Decode(data, v)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var names []string
+		for _, k := range undecoded {
+			names = append(names, k.String())
+		}
+		return fmt.Errorf("unknown fields: %s", strings.Join(names, ", "))
+	}
+	return nil
+}
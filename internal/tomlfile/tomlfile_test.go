@@ -0,0 +1,24 @@
+package tomlfile
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	var v struct {
+		Name string `toml:"name"`
+		Size struct {
+			Width int `toml:"width"`
+		} `toml:"size"`
+	}
+	if err := Decode("name = \"a\"\n[size]\nwidth = 3\n", &v); err != nil || v.Name != "a" || v.Size.Width != 3 {
+		t.Errorf("Expected a clean decode, got %+v, %v", v, err)
+	}
+
+	err := Decode("name = \"a\"\ncolour = 1\n[size]\nheight = 2\n", &v)
+	if err == nil || err.Error() != "unknown fields: colour, size.height" {
+		t.Errorf("Expected every unknown field to be reported, got %v", err)
+	}
+
+	if err := Decode("name = ", &v); err == nil {
+		t.Errorf("Expected a syntax error")
+	}
+}